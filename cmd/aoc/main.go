@@ -0,0 +1,55 @@
+// Command aoc runs the daily solutions.
+//
+//	aoc run [-j N] [-input input.txt] [-timeout 30s] [-dir dir] all|<day>...
+//	aoc run [-cpuprofile dir] [-memprofile dir] [-trace dir] [-pprof-http addr] <day>
+//	aoc new [-dir dir] <year> <day>
+//
+// Each day is run with `go run ./dayNN` from the repository root, reading its
+// puzzle input from dayNN/input.txt. With -timeout each part of a day gives up
+// after that long and reports how far it got. The profiling flags are also
+// passed through, each part of the day writes its own files, for example
+// dir/day19-part-1.cpu.pprof.
+//
+// New writes the skeleton for a day: an aoc.Solver, an embedded sample.txt and
+// a test to fill in with the sample answers. Days for 2022 go at the top of the
+// repository, other years in a directory named after the year, or -dir. Run
+// takes the same -dir to find them.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: aoc run [-j N] [-input file] [-timeout d] [-dir dir] all|<day>...\n")
+	fmt.Fprintf(os.Stderr, "       aoc run [-cpuprofile dir] [-memprofile dir] [-trace dir] [-pprof-http addr] <day>\n")
+	fmt.Fprintf(os.Stderr, "       aoc new [-dir dir] <year> <day>\n")
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "run":
+		err = run(ctx, os.Args[2:])
+	case "new":
+		err = newDay(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
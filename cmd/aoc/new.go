@@ -0,0 +1,133 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/template"
+)
+
+// year is the year the days at the top of the repository are for, other
+// years go in a directory named after the year.
+const year = 2022
+
+// firstYear is the first Advent of Code.
+const firstYear = 2015
+
+var dayTemplates = map[string]*template.Template{
+	"main.go": template.Must(template.New("main.go").Parse(`package main
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
+)
+
+// https://adventofcode.com/{{.Year}}/day/{{.Day}}
+
+//go:embed sample.txt
+var sample string
+
+type solver struct{}
+
+func (solver) Part1(ctx context.Context, input string) (any, error) {
+	return nil, fmt.Errorf("not solved yet")
+}
+
+func (solver) Part2(ctx context.Context, input string) (any, error) {
+	return nil, fmt.Errorf("not solved yet")
+}
+
+func main() {
+	aoc.Main(solver{})
+}
+`)),
+	"main_test.go": template.Must(template.New("main_test.go").Parse(`package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSample(t *testing.T) {
+	cases := []struct {
+		name string
+		part func(context.Context, string) (any, error)
+		want any
+	}{
+		{"part 1", solver{}.Part1, nil}, // TODO: answer from the puzzle text
+		{"part 2", solver{}.Part2, nil}, // TODO: answer from the puzzle text
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.want == nil {
+				t.Skip("TODO: no expected answer yet")
+			}
+			got, err := tc.part(context.Background(), sample)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+`)),
+	"sample.txt": template.Must(template.New("sample.txt").Parse(``)),
+}
+
+// newDay writes the skeleton for a day into a new dayNN directory. There's
+// nothing to register, run finds every dayNN directory with a main.go.
+func newDay(args []string) error {
+	fs := flag.NewFlagSet("new", flag.ExitOnError)
+	root := fs.String("dir", "", "directory to create the day in, the default is the top of the repository for 2022 and the year for other years")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: aoc new [-dir dir] <year> <day>")
+	}
+
+	y, err := strconv.Atoi(fs.Arg(0))
+	if err != nil || y < firstYear {
+		return fmt.Errorf("year %q: must be %d or later", fs.Arg(0), firstYear)
+	}
+	d, err := strconv.Atoi(fs.Arg(1))
+	if err != nil || d < 1 || d > 25 {
+		return fmt.Errorf("day %q: must be 1 to 25", fs.Arg(1))
+	}
+	if *root == "" {
+		*root = "."
+		if y != year {
+			*root = strconv.Itoa(y)
+		}
+	}
+
+	dir := filepath.Join(*root, fmt.Sprintf("day%02d", d))
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	data := struct{ Year, Day int }{y, d}
+	for name, tmpl := range dayTemplates {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		if err := tmpl.Execute(f, data); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("created %s, put the sample in %s/sample.txt and the answers in %s/main_test.go\n", dir, dir, dir)
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewDay(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("needs the go command")
+	}
+	repo, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A module outside the repository that uses this one, so the new day is
+	// built the same way as one inside it.
+	dir := t.TempDir()
+	gomod := fmt.Sprintf("module aoctest\n\ngo 1.19\n\nrequire github.com/mikehelmick/AdventOfCode2022 v0.0.0\n\nreplace github.com/mikehelmick/AdventOfCode2022 => %s\n", repo)
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := newDay([]string{"-dir", dir, "2023", "7"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := newDay([]string{"-dir", dir, "2023", "7"}); err == nil {
+		t.Errorf("wrote over a day that already exists")
+	}
+
+	// The skeleton builds and its test runs, skipping the missing answers.
+	cmd := exec.Command("go", "test", "./day07")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test ./day07: %v\n%s", err, out)
+	}
+}
+
+func TestNewDayArgs(t *testing.T) {
+	for _, args := range [][]string{
+		{"2014", "1"},
+		{"2022", "0"},
+		{"2022", "26"},
+		{"2022"},
+	} {
+		if err := newDay(append([]string{"-dir", t.TempDir()}, args...)); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/parallel"
+)
+
+type result struct {
+	day     string
+	output  []byte
+	elapsed time.Duration
+	err     error
+}
+
+// parseArgs parses the flags in args, which can come before, after or
+// between the days, and returns the days. A FlagSet stops at the first day,
+// so the rest are parsed again after taking it off.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	days := make([]string, 0)
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return days, nil
+		}
+		days = append(days, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	jobs := fs.Int("j", 1, "number of days to run at the same time")
+	root := fs.String("dir", ".", "directory the days are in, like 2023 for days made with aoc new 2023 <day>")
+	input := fs.String("input", "input.txt", "input file name, inside each day's directory")
+	timeout := fs.Duration("timeout", 0, "passed to each day, give up on a part after this long")
+	cpuProfile := fs.String("cpuprofile", "", "write a CPU profile of each part into this directory")
+	memProfile := fs.String("memprofile", "", "write a heap profile after each part into this directory")
+	traceDir := fs.String("trace", "", "write an execution trace of each part into this directory")
+	pprofHTTP := fs.String("pprof-http", "", "serve live profiles on this address while the day runs, like localhost:6060")
+	names, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	days, err := selectDays(*root, names)
+	if err != nil {
+		return err
+	}
+
+	// Flags for the days themselves, see package aoc.
+	var dayArgs []string
+	if *timeout > 0 {
+		dayArgs = append(dayArgs, "-timeout", timeout.String())
+	}
+	profiling := false
+	// profileDirs are where the day should write files.
+	var profileDirs []string
+	for _, f := range []struct {
+		name  string
+		value string
+	}{
+		{"cpuprofile", *cpuProfile},
+		{"memprofile", *memProfile},
+		{"trace", *traceDir},
+		{"pprof-http", *pprofHTTP},
+	} {
+		if f.value != "" {
+			dayArgs = append(dayArgs, "-"+f.name, f.value)
+			profiling = true
+			if f.name != "pprof-http" {
+				profileDirs = append(profileDirs, f.value)
+			}
+		}
+	}
+	// Profiles of days running side by side would just measure each other.
+	if profiling && len(days) != 1 {
+		return fmt.Errorf("profiling needs a single day, got %d", len(days))
+	}
+	// The pprof handlers are only built in when they're wanted.
+	var buildArgs []string
+	if *pprofHTTP != "" {
+		buildArgs = append(buildArgs, "-tags", "pprof")
+	}
+
+	// Output from each day is buffered, then printed in order as soon as that
+	// day and all the ones before it are done.
+	done := make([]chan *result, len(days))
+	for i := range done {
+		done[i] = make(chan *result, 1)
+	}
+	st := newStatus(os.Stderr, aoc.IsTerminal(os.Stderr))
+	idx := make([]int, len(days))
+	for i := range idx {
+		idx[i] = i
+	}
+	started := time.Now()
+	go parallel.ForEach(ctx, *jobs, idx, func(ctx context.Context, i int) error {
+		done[i] <- runDay(ctx, days[i], *input, buildArgs, dayArgs, st)
+		return nil
+	})
+
+	failed := 0
+	for i := range days {
+		var r *result
+		select {
+		case r = <-done[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if r.err == nil {
+			r.err = checkProfiles(r.day, started, profileDirs)
+		}
+		st.clear()
+		fmt.Printf("==== %s (%v) ====\n", r.day, r.elapsed.Round(time.Millisecond))
+		os.Stdout.Write(r.output)
+		if r.err != nil {
+			failed++
+			fmt.Printf("==== %s FAILED: %v ====\n", r.day, r.err)
+		}
+		st.redraw()
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d days failed", failed, len(days))
+	}
+	return nil
+}
+
+// checkProfiles returns an error if day didn't write a profile into each of
+// dirs since it was started. Only parts run with aoc.Part or aoc.Main are
+// profiled, other days would otherwise succeed without writing anything.
+func checkProfiles(day string, since time.Time, dirs []string) error {
+	// Allow for file systems that only keep times to the second.
+	since = since.Truncate(time.Second)
+	prefix := filepath.Base(day) + "-"
+	for _, dir := range dirs {
+		matches, err := filepath.Glob(filepath.Join(dir, prefix+"*"))
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, m := range matches {
+			if fi, err := os.Stat(m); err == nil && !fi.ModTime().Before(since) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no profile written to %s, %s doesn't run its parts with aoc.Part or aoc.Main", dir, day)
+		}
+	}
+	return nil
+}
+
+func runDay(ctx context.Context, day string, input string, buildArgs, dayArgs []string, st *status) *result {
+	r := &result{day: day}
+	in, err := os.Open(filepath.Join(day, input))
+	if err != nil {
+		r.err = err
+		return r
+	}
+	defer in.Close()
+
+	// Progress events are picked out of the output and shown in the status.
+	var out bytes.Buffer
+	filter := &eventFilter{day: day, out: &out, status: st}
+	pkg := day
+	if !filepath.IsAbs(pkg) {
+		pkg = "./" + filepath.ToSlash(pkg)
+	}
+	args := append([]string{"run"}, buildArgs...)
+	args = append(args, pkg)
+	args = append(args, dayArgs...)
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Stdin = in
+	cmd.Stdout = filter
+	cmd.Stderr = filter
+
+	start := time.Now()
+	r.err = cmd.Run()
+	filter.flush()
+	r.elapsed = time.Since(start)
+	r.output = out.Bytes()
+	return r
+}
+
+// selectDays turns the arguments into day directories under root, "all" is
+// every day.
+func selectDays(root string, args []string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no days given, use all or a list of days")
+	}
+	if len(args) == 1 && args[0] == "all" {
+		matches, err := filepath.Glob(filepath.Join(root, "day*", "main.go"))
+		if err != nil {
+			return nil, err
+		}
+		days := make([]string, 0, len(matches))
+		for _, m := range matches {
+			days = append(days, filepath.Dir(m))
+		}
+		sort.Strings(days)
+		return days, nil
+	}
+
+	days := make([]string, 0, len(args))
+	for _, a := range args {
+		day := filepath.Join(root, a)
+		if n, err := strconv.Atoi(a); err == nil {
+			day = filepath.Join(root, fmt.Sprintf("day%02d", n))
+		}
+		if _, err := os.Stat(filepath.Join(day, "main.go")); err != nil {
+			return nil, fmt.Errorf("unknown day %q", a)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseArgs(t *testing.T) {
+	cases := []struct {
+		args []string
+		days string
+		jobs int
+	}{
+		{[]string{"all"}, "[all]", 1},
+		{[]string{"-j", "4", "all"}, "[all]", 4},
+		{[]string{"all", "-j", "4"}, "[all]", 4},
+		{[]string{"1", "-j", "2", "3"}, "[1 3]", 2},
+		{[]string{"-j=3"}, "[]", 3},
+	}
+	for _, c := range cases {
+		fs := flag.NewFlagSet("run", flag.ContinueOnError)
+		jobs := fs.Int("j", 1, "")
+		days, err := parseArgs(fs, c.args)
+		if err != nil {
+			t.Errorf("%v: %v", c.args, err)
+			continue
+		}
+		if got := fmt.Sprint(days); got != c.days || *jobs != c.jobs {
+			t.Errorf("%v got: %v -j %v want: %v -j %v", c.args, got, *jobs, c.days, c.jobs)
+		}
+	}
+}
+
+func TestParseArgsBadFlag(t *testing.T) {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Int("j", 1, "")
+	if _, err := parseArgs(fs, []string{"all", "-k"}); err == nil {
+		t.Errorf("unknown flag after a day wasn't an error")
+	}
+}
+
+func TestCheckProfiles(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now()
+	if err := os.WriteFile(filepath.Join(dir, "day19-part-1.cpu.pprof"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := filepath.Join(dir, "day15-part-1.cpu.pprof")
+	if err := os.WriteFile(old, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(old, start.Add(-time.Hour), start.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkProfiles("day19", start, []string{dir}); err != nil {
+		t.Errorf("day19 wrote a profile, got: %v", err)
+	}
+	if err := checkProfiles("2023/day19", start, []string{dir}); err != nil {
+		t.Errorf("profiles are named after the day's directory, got: %v", err)
+	}
+	if err := checkProfiles("day11", start, []string{dir}); err == nil {
+		t.Errorf("day11 wrote no profile, expected an error")
+	}
+	if err := checkProfiles("day15", start, []string{dir}); err == nil {
+		t.Errorf("day15's profile is from an earlier run, expected an error")
+	}
+	if err := checkProfiles("day19", start, []string{dir, t.TempDir()}); err == nil {
+		t.Errorf("second directory is empty, expected an error")
+	}
+}
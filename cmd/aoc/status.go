@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
+)
+
+// status shows the latest progress from every running day. On a terminal
+// that's a single line that is redrawn, otherwise each event is passed on as
+// JSON with the day filled in.
+type status struct {
+	mu     sync.Mutex
+	w      io.Writer
+	tty    bool
+	events map[string]*aoc.Event
+}
+
+func newStatus(w io.Writer, tty bool) *status {
+	return &status{
+		w:      w,
+		tty:    tty,
+		events: make(map[string]*aoc.Event),
+	}
+}
+
+func (s *status) update(day string, e *aoc.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.Day = day
+	if !s.tty {
+		b, err := json.Marshal(e)
+		if err == nil {
+			s.w.Write(append(b, '\n'))
+		}
+		return
+	}
+	if e.Done {
+		delete(s.events, day)
+	} else {
+		s.events[day] = e
+	}
+	s.draw()
+}
+
+// clear removes the status line so other output can be written, call redraw
+// after.
+func (s *status) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tty {
+		fmt.Fprint(s.w, "\r\033[K")
+	}
+}
+
+func (s *status) redraw() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tty {
+		s.draw()
+	}
+}
+
+func (s *status) draw() {
+	days := make([]string, 0, len(s.events))
+	for d := range s.events {
+		days = append(days, d)
+	}
+	sort.Strings(days)
+	parts := make([]string, len(days))
+	for i, d := range days {
+		parts[i] = s.events[d].String()
+	}
+	fmt.Fprintf(s.w, "\r\033[K%s", strings.Join(parts, " | "))
+}
+
+// eventFilter passes a day's output through to out, except for progress
+// events which go to the status.
+type eventFilter struct {
+	day     string
+	out     *bytes.Buffer
+	status  *status
+	partial []byte
+}
+
+func (f *eventFilter) Write(p []byte) (int, error) {
+	f.partial = append(f.partial, p...)
+	for {
+		i := bytes.IndexByte(f.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := f.partial[:i+1]
+		if e, ok := aoc.ParseEvent(string(line[:i])); ok {
+			f.status.update(f.day, e)
+		} else {
+			f.out.Write(line)
+		}
+		f.partial = f.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush writes out anything left without a trailing newline.
+func (f *eventFilter) flush() {
+	f.out.Write(f.partial)
+	f.partial = nil
+}
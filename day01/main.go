@@ -1,66 +1,73 @@
 package main
 
 import (
-	"bufio"
+	"errors"
+	"flag"
+	"io"
 	"log"
 	"os"
-	"sort"
-	"strconv"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/list"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
 )
 
+var top = flag.Int("top", 3, "how many of the elves carrying the most to report")
+
+// ElfStash is the running total for one elf, elves are numbered from 1 in
+// input order.
 type ElfStash struct {
-	calories []int64
+	Index int
+	Total int64
 }
 
-func New() *ElfStash {
-	return &ElfStash{
-		calories: make([]int64, 0),
-	}
-}
+// Richest reads the elves from r and returns the n carrying the most, most
+// first.
+func Richest(name string, r io.Reader, n int) ([]ElfStash, error) {
+	reader := straid.NewBlockReader(name, r)
 
-func (e *ElfStash) Add(v int64) {
-	e.calories = append(e.calories, v)
+	// Each elf is a block of lines separated by a blank line. Only the largest
+	// stashes are kept, so the input can be as long as it likes.
+	best := list.NewTop(n, func(a, b ElfStash) bool { return a.Total < b.Total })
+	for elf := 1; reader.Scan(); elf++ {
+		b := reader.Block()
+		e := ElfStash{Index: elf}
+		for i, line := range b.Lines {
+			v, err := straid.ParseInt(line)
+			if err != nil {
+				return nil, b.Wrap(i, err)
+			}
+			e.Total += v
+		}
+		best.Push(e)
+	}
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+	if best.Len() == 0 {
+		return nil, errors.New("no elves in the input")
+	}
+	return best.Values(), nil
 }
 
-func (e *ElfStash) Sum() (sum int64) {
-	sum = 0
-	for _, v := range e.calories {
-		sum += v
+// report logs each of elves and the answers, elves is most first.
+func report(elves []ElfStash) {
+	for i, e := range elves {
+		log.Printf("#%v: elf %v has %v", i+1, e.Index, e.Total)
 	}
-	log.Printf("sum: %+v == %v", e.calories, sum)
-	return
+	log.Printf("Top1: %v", elves[0].Total)
+	log.Printf("Top%v: %v", len(elves), list.Sum(list.Map(elves, func(e ElfStash) int64 { return e.Total })))
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-
-	elves := make([]*ElfStash, 0)
-
-	e := New()
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
-			elves = append(elves, e)
-			e = New()
-			continue
-		}
-
-		i, err := strconv.ParseInt(line, 10, 64)
-		if err != nil {
-			log.Fatalf("cann't parse value: %v", err)
-		}
-		e.Add(i)
+	aoc.Parse()
+	if *top < 1 {
+		log.Fatalf("-top must be at least 1, got %v", *top)
 	}
-	elves = append(elves, e)
-
-	sort.Slice(elves, func(i, j int) bool {
-		return elves[i].Sum() > elves[j].Sum()
-	})
-
-	top3 := elves[0].Sum() + elves[1].Sum() + elves[2].Sum()
-	log.Printf("Top3: %v", top3)
 
-	if err := scanner.Err(); err != nil {
-		log.Println(err)
+	elves, err := Richest("day01 input", os.Stdin, *top)
+	if err != nil {
+		log.Fatal(err)
 	}
+	report(elves)
 }
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"strings"
+	"testing"
+)
+
+// sample is the calorie list from the puzzle, elf 4 carries the most.
+const sample = "1000\n2000\n3000\n\n4000\n\n5000\n6000\n\n7000\n8000\n9000\n\n10000\n"
+
+func TestRichest(t *testing.T) {
+	cases := []struct {
+		n    int
+		want []ElfStash
+	}{
+		{1, []ElfStash{{4, 24000}}},
+		{3, []ElfStash{{4, 24000}, {3, 11000}, {5, 10000}}},
+		// Asking for more than there are gets everyone.
+		{9, []ElfStash{{4, 24000}, {3, 11000}, {5, 10000}, {1, 6000}, {2, 4000}}},
+	}
+	for _, c := range cases {
+		got, err := Richest("test", strings.NewReader(sample), c.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(c.want) {
+			t.Fatalf("top %v got: %v want: %v", c.n, got, c.want)
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("top %v got: %v want: %v", c.n, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestRichestErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"not a number", "1000\n\n20x0\n", `test:3:1: "20x0": not an integer: invalid syntax`},
+		{"empty", "\n\n", "no elves in the input"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := Richest("test", strings.NewReader(c.input), 3)
+			if err == nil || err.Error() != c.want {
+				t.Errorf("got: %v want: %v", err, c.want)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	var out bytes.Buffer
+	defer func(w io.Writer, flags int) {
+		log.SetOutput(w)
+		log.SetFlags(flags)
+	}(log.Writer(), log.Flags())
+	log.SetOutput(&out)
+	log.SetFlags(0)
+
+	report([]ElfStash{{4, 24000}, {3, 11000}, {5, 10000}})
+	want := "#1: elf 4 has 24000\n#2: elf 3 has 11000\n#3: elf 5 has 10000\nTop1: 24000\nTop3: 45000\n"
+	if got := out.String(); got != want {
+		t.Errorf("got:\n%vwant:\n%v", got, want)
+	}
+}
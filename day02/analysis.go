@@ -0,0 +1,62 @@
+package main
+
+import "log"
+
+// Analysis compares the guide with other ways to play against the same
+// opponent moves.
+type Analysis struct {
+	Guide   int
+	Optimal int
+	Worst   int
+	// Random is the expected score picking a shape uniformly each round.
+	Random float64
+	// Deltas is the optimal score minus the guide's for each round.
+	Deltas []int
+}
+
+// Range returns the lowest and highest score you can get against opp.
+func (r *Rules) Range(opp int) (int, int) {
+	lo, hi := -1, -1
+	for you := range r.Shapes {
+		s := r.Score(&Round{Opponent: opp, You: you})
+		if lo < 0 || s < lo {
+			lo = s
+		}
+		if hi < 0 || s > hi {
+			hi = s
+		}
+	}
+	return lo, hi
+}
+
+// Mean is the average score of every shape against opp.
+func (r *Rules) Mean(opp int) float64 {
+	sum := 0
+	for you := range r.Shapes {
+		sum += r.Score(&Round{Opponent: opp, You: you})
+	}
+	return float64(sum) / float64(len(r.Shapes))
+}
+
+func (r *Rules) Analyze(rounds []*Round) *Analysis {
+	a := &Analysis{Deltas: make([]int, len(rounds))}
+	for i, rd := range rounds {
+		score := r.Score(rd)
+		lo, hi := r.Range(rd.Opponent)
+		a.Guide += score
+		a.Optimal += hi
+		a.Worst += lo
+		a.Random += r.Mean(rd.Opponent)
+		a.Deltas[i] = hi - score
+	}
+	return a
+}
+
+func (a *Analysis) Print(mode Mode) {
+	for i, d := range a.Deltas {
+		if d != 0 {
+			log.Printf("%v round %v: %v below optimal", mode, i+1, d)
+		}
+	}
+	log.Printf("%v guide: %v optimal: %v worst: %v random: %.1f", mode, a.Guide, a.Optimal, a.Worst, a.Random)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRangeMean(t *testing.T) {
+	r := mustRules(t, "rps")
+	// Against each opponent shape: the worst and best score you can get, and
+	// the average of all three.
+	want := []string{"3 8 5", "1 9 5", "2 7 5"}
+	for opp := range r.Shapes {
+		lo, hi := r.Range(opp)
+		if got := fmt.Sprint(lo, hi, r.Mean(opp)); got != want[opp] {
+			t.Errorf("against %v got: %v want: %v", r.Shapes[opp].Name, got, want[opp])
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	r := mustRules(t, "rps")
+	cases := []struct {
+		mode   Mode
+		guide  int
+		deltas string
+	}{
+		{SHAPE, 15, "[0 8 1]"},
+		{OUTCOME, 12, "[4 8 0]"},
+	}
+	for _, c := range cases {
+		a := r.Analyze(parseGuide(t, r, sampleGuide, c.mode))
+		if a.Guide != c.guide || a.Optimal != 24 || a.Worst != 6 || a.Random != 15 {
+			t.Errorf("%v got guide: %v optimal: %v worst: %v random: %v, want %v 24 6 15",
+				c.mode, a.Guide, a.Optimal, a.Worst, a.Random, c.guide)
+		}
+		if got := fmt.Sprint(a.Deltas); got != c.deltas {
+			t.Errorf("%v deltas got: %v want: %v", c.mode, got, c.deltas)
+		}
+	}
+}
@@ -1,96 +1,94 @@
 package main
 
 import (
-	"bufio"
+	"flag"
+	"fmt"
 	"log"
 	"os"
 	"strings"
-)
 
-const (
-	ROCK int = iota
-	PAPER
-	SCISSORS
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
 )
 
 var (
-	values = map[string]int{
-		"A": ROCK, "B": PAPER, "C": SCISSORS,
-		"X": ROCK, "Y": PAPER, "Z": SCISSORS,
-	}
-	shapes = map[int]int{
-		ROCK:     1,
-		PAPER:    2,
-		SCISSORS: 3,
-	}
-
-	outcomes = map[string]map[string]string{
-		// lose
-		"X": map[string]string{"A": "Z", "B": "X", "C": "Y"},
-		// draw
-		"Y": map[string]string{"A": "X", "B": "Y", "C": "Z"},
-		// win
-		"Z": map[string]string{"A": "Y", "B": "Z", "C": "X"},
-	}
+	game      = flag.String("game", "rps", "rules to play by: rps, rpsls or cycle")
+	cycle     = flag.String("shapes", "", "comma separated shapes for -game cycle, an odd number in the order they beat each other")
+	modeNames = flag.String("mode", "shape,outcome", "comma separated ways to read the second column: shape, outcome")
+	analyze   = flag.Bool("analyze", false, "compare the guide with the best, worst and random play, round by round")
 )
 
-func winLoseDraw(opp, you int) int {
-	if opp == you {
-		return 3
-	}
-	if (you == ROCK && opp == SCISSORS) ||
-		(you == PAPER && opp == ROCK) ||
-		(you == SCISSORS && opp == PAPER) {
-		return 6
-	}
-	return 0
-}
-
+// Round is one line of the strategy guide, as indexes into Rules.Shapes.
 type Round struct {
-	Opponent string
-	You      string
+	Opponent int
+	You      int
 }
 
-func New(opp, outcome string) *Round {
-	return &Round{
-		Opponent: opp,
-		You:      outcomes[outcome][opp],
-	}
+func (r *Round) Print(rules *Rules) {
+	log.Printf("%v %v %v\n", rules.Shapes[r.Opponent].Name, rules.Shapes[r.You].Name, rules.Score(r))
 }
 
-func (r *Round) Score() int {
-	o := values[r.Opponent]
-	y := values[r.You]
-
-	shapeScore := shapes[y]
-	return winLoseDraw(o, y) + shapeScore
+func loadRules() (*Rules, error) {
+	if *game == "cycle" {
+		return CycleRules(strings.Split(*cycle, ",")...)
+	}
+	f, ok := games[*game]
+	if !ok {
+		return nil, fmt.Errorf("unknown game %q", *game)
+	}
+	return f()
 }
 
-func (r *Round) Print() {
-	log.Printf("%v %v %v\n", r.Opponent, r.You, r.Score())
+// label is what a mode's score is logged as, reading the second column as
+// a shape is part 1 of the puzzle and as an outcome part 2.
+func label(m Mode) string {
+	if m == SHAPE {
+		return "part1"
+	}
+	return "part2"
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-
-	var part1 int64
-	var score int64
-	for scanner.Scan() {
-		line := scanner.Text()
+	aoc.Parse()
 
-		parts := strings.Split(line, " ")
-
-		r1 := Round{Opponent: strings.TrimSpace(parts[0]), You: strings.TrimSpace(parts[1])}
-		part1 += int64(r1.Score())
+	rules, err := loadRules()
+	if err != nil {
+		log.Fatal(err)
+	}
+	modes := make([]Mode, 0, 2)
+	for _, m := range strings.Split(*modeNames, ",") {
+		if mode := Mode(m); mode != SHAPE && mode != OUTCOME {
+			log.Fatalf("unknown mode %q", m)
+		}
+		modes = append(modes, Mode(m))
+	}
 
-		r2 := New(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
-		score += int64(r2.Score())
+	reader := straid.NewLineReader("day02 input", os.Stdin)
+	reader.SkipBlank = true
+
+	scores := make([]int64, len(modes))
+	rounds := make([][]*Round, len(modes))
+	for reader.Scan() {
+		for i, mode := range modes {
+			r, err := rules.Parse(reader.Text(), mode)
+			if err != nil {
+				log.Fatal(reader.Wrap(err))
+			}
+			scores[i] += int64(rules.Score(r))
+			if *analyze {
+				rounds[i] = append(rounds[i], r)
+			}
+		}
 	}
 
-	log.Printf("part1 score: %v\n", part1)
-	log.Printf("part2 score: %v\n", score)
+	for i, mode := range modes {
+		log.Printf("%v score: %v\n", label(mode), scores[i])
+		if *analyze {
+			rules.Analyze(rounds[i]).Print(mode)
+		}
+	}
 
-	if err := scanner.Err(); err != nil {
+	if err := reader.Err(); err != nil {
 		log.Println(err)
 	}
 }
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Outcome int
+
+const (
+	LOSE Outcome = iota
+	DRAW
+	WIN
+)
+
+func (o Outcome) String() string {
+	return [...]string{"lose", "draw", "win"}[o]
+}
+
+type Shape struct {
+	Name  string
+	Score int
+}
+
+// Rules is the data for a rock paper scissors style game: the shapes, which
+// shape beats which, the scoring and what the strategy guide letters mean.
+type Rules struct {
+	Shapes []Shape
+	// beats[a][b] is true if shape a beats shape b.
+	beats  [][]bool
+	Points map[Outcome]int
+
+	// Opponent is the first column of the guide, You and Want are the two ways
+	// to read the second column.
+	Opponent map[string]int
+	You      map[string]int
+	Want     map[string]Outcome
+}
+
+// NewRules checks that beats decides every pair of different shapes exactly
+// one way. The guide letters are A, B, C... for the opponent and end at Z for
+// you, so three shapes are A-C and X-Z.
+func NewRules(shapes []Shape, beats map[string][]string) (*Rules, error) {
+	n := len(shapes)
+	if n < 2 || n > 13 {
+		return nil, fmt.Errorf("need 2 to 13 shapes, got %v", n)
+	}
+	idx := make(map[string]int, n)
+	for i, s := range shapes {
+		if _, ok := idx[s.Name]; ok {
+			return nil, fmt.Errorf("shape %q is listed twice", s.Name)
+		}
+		idx[s.Name] = i
+	}
+
+	r := &Rules{
+		Shapes:   shapes,
+		beats:    make([][]bool, n),
+		Points:   map[Outcome]int{LOSE: 0, DRAW: 3, WIN: 6},
+		Opponent: make(map[string]int, n),
+		You:      make(map[string]int, n),
+		Want:     map[string]Outcome{"X": LOSE, "Y": DRAW, "Z": WIN},
+	}
+	for i := range r.beats {
+		r.beats[i] = make([]bool, n)
+	}
+	for winner, losers := range beats {
+		w, ok := idx[winner]
+		if !ok {
+			return nil, fmt.Errorf("unknown shape %q", winner)
+		}
+		for _, loser := range losers {
+			l, ok := idx[loser]
+			if !ok {
+				return nil, fmt.Errorf("unknown shape %q", loser)
+			}
+			if w == l {
+				return nil, fmt.Errorf("%v can't beat itself", winner)
+			}
+			r.beats[w][l] = true
+		}
+	}
+	for a := 0; a < n; a++ {
+		for b := a + 1; b < n; b++ {
+			if r.beats[a][b] == r.beats[b][a] {
+				return nil, fmt.Errorf("%v against %v needs exactly one winner", shapes[a].Name, shapes[b].Name)
+			}
+		}
+	}
+
+	for i := range shapes {
+		r.Opponent[string(rune('A'+i))] = i
+		r.You[string(rune('Z'-n+1+i))] = i
+	}
+	return r, nil
+}
+
+// Cycle is the beats relation for an odd number of shapes in a circle, where
+// each beats the half of the others just before it. Rock, Paper, Scissors is
+// the usual game.
+func Cycle(names ...string) (map[string][]string, error) {
+	n := len(names)
+	if n%2 == 0 {
+		return nil, fmt.Errorf("a cycle needs an odd number of shapes, got %v", n)
+	}
+	beats := make(map[string][]string, n)
+	for i, name := range names {
+		for d := 1; d <= n/2; d++ {
+			beats[name] = append(beats[name], names[(i-d+n)%n])
+		}
+	}
+	return beats, nil
+}
+
+// CycleRules is a Cycle game where shapes score 1, 2, 3... in the order given.
+func CycleRules(names ...string) (*Rules, error) {
+	beats, err := Cycle(names...)
+	if err != nil {
+		return nil, err
+	}
+	shapes := make([]Shape, len(names))
+	for i, name := range names {
+		shapes[i] = Shape{Name: name, Score: i + 1}
+	}
+	return NewRules(shapes, beats)
+}
+
+// games are the built in rule sets.
+var games = map[string]func() (*Rules, error){
+	"rps": func() (*Rules, error) {
+		return CycleRules("rock", "paper", "scissors")
+	},
+	"rpsls": func() (*Rules, error) {
+		// Scored in the order Sheldon lists them, the cycle order is different.
+		beats, err := Cycle("rock", "spock", "paper", "lizard", "scissors")
+		if err != nil {
+			return nil, err
+		}
+		return NewRules([]Shape{
+			{"rock", 1}, {"paper", 2}, {"scissors", 3}, {"lizard", 4}, {"spock", 5},
+		}, beats)
+	},
+}
+
+// Outcome is how the round goes for you.
+func (r *Rules) Outcome(opp, you int) Outcome {
+	switch {
+	case r.beats[you][opp]:
+		return WIN
+	case r.beats[opp][you]:
+		return LOSE
+	}
+	return DRAW
+}
+
+// Choose picks the shape to play for the outcome you want, the highest scoring
+// one if there's a choice.
+func (r *Rules) Choose(opp int, want Outcome) (int, error) {
+	best := -1
+	for you, s := range r.Shapes {
+		if r.Outcome(opp, you) == want && (best < 0 || s.Score > r.Shapes[best].Score) {
+			best = you
+		}
+	}
+	if best < 0 {
+		return 0, fmt.Errorf("no shape will %v against %v", want, r.Shapes[opp].Name)
+	}
+	return best, nil
+}
+
+func (r *Rules) Score(rd *Round) int {
+	return r.Points[r.Outcome(rd.Opponent, rd.You)] + r.Shapes[rd.You].Score
+}
+
+// Mode is how the second column of the guide is read.
+type Mode string
+
+const (
+	SHAPE   Mode = "shape"
+	OUTCOME Mode = "outcome"
+)
+
+// Parse reads a line of the strategy guide, like "A Y".
+func (r *Rules) Parse(line string, mode Mode) (*Round, error) {
+	parts := strings.Fields(line)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("want 2 columns, got %q", line)
+	}
+	opp, ok := r.Opponent[parts[0]]
+	if !ok {
+		return nil, fmt.Errorf("unknown opponent move %q", parts[0])
+	}
+
+	switch mode {
+	case SHAPE:
+		you, ok := r.You[parts[1]]
+		if !ok {
+			return nil, fmt.Errorf("unknown move %q", parts[1])
+		}
+		return &Round{Opponent: opp, You: you}, nil
+	case OUTCOME:
+		want, ok := r.Want[parts[1]]
+		if !ok {
+			return nil, fmt.Errorf("unknown outcome %q", parts[1])
+		}
+		you, err := r.Choose(opp, want)
+		if err != nil {
+			return nil, err
+		}
+		return &Round{Opponent: opp, You: you}, nil
+	}
+	return nil, fmt.Errorf("unknown mode %q", mode)
+}
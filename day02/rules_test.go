@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+// sampleGuide is the strategy guide from the puzzle.
+var sampleGuide = []string{"A Y", "B X", "C Z"}
+
+func mustRules(t *testing.T, game string) *Rules {
+	t.Helper()
+	r, err := games[game]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// parseGuide reads every line of the guide with mode.
+func parseGuide(t *testing.T, r *Rules, lines []string, mode Mode) []*Round {
+	t.Helper()
+	rounds := make([]*Round, len(lines))
+	for i, l := range lines {
+		rd, err := r.Parse(l, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rounds[i] = rd
+	}
+	return rounds
+}
+
+func TestCycle(t *testing.T) {
+	cases := []struct {
+		names []string
+		want  map[string]string
+	}{
+		{
+			names: []string{"rock", "paper", "scissors"},
+			want:  map[string]string{"rock": "[scissors]", "paper": "[rock]", "scissors": "[paper]"},
+		},
+		{
+			names: []string{"rock", "spock", "paper", "lizard", "scissors"},
+			want: map[string]string{
+				"rock":     "[lizard scissors]",
+				"spock":    "[rock scissors]",
+				"paper":    "[rock spock]",
+				"lizard":   "[paper spock]",
+				"scissors": "[lizard paper]",
+			},
+		},
+	}
+	for _, c := range cases {
+		beats, err := Cycle(c.names...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range c.names {
+			losers := append([]string(nil), beats[name]...)
+			sort.Strings(losers)
+			if got := fmt.Sprint(losers); got != c.want[name] {
+				t.Errorf("%v beats %v, want %v", name, got, c.want[name])
+			}
+		}
+	}
+
+	if _, err := Cycle("rock", "paper"); err == nil {
+		t.Errorf("expected an error for an even number of shapes")
+	}
+}
+
+func TestNewRulesErrors(t *testing.T) {
+	abc := []Shape{{"a", 1}, {"b", 2}, {"c", 3}}
+	cases := []struct {
+		name   string
+		shapes []Shape
+		beats  map[string][]string
+	}{
+		{"one shape", []Shape{{"a", 1}}, nil},
+		{"listed twice", []Shape{{"a", 1}, {"a", 2}}, map[string][]string{"a": {"a"}}},
+		{"unknown winner", abc, map[string][]string{"d": {"a"}}},
+		{"unknown loser", abc, map[string][]string{"a": {"d"}}},
+		{"beats itself", abc, map[string][]string{"a": {"a", "b"}, "b": {"c"}, "c": {"a"}}},
+		{"no winner", abc, map[string][]string{"a": {"b"}, "b": {"c"}}},
+		{"both win", abc, map[string][]string{"a": {"b", "c"}, "b": {"a", "c"}}},
+	}
+	for _, c := range cases {
+		if _, err := NewRules(c.shapes, c.beats); err == nil {
+			t.Errorf("%v: expected an error", c.name)
+		}
+	}
+
+	// A game doesn't have to be a cycle, as long as every pair is decided.
+	if _, err := NewRules(abc, map[string][]string{"a": {"b", "c"}, "b": {"c"}}); err != nil {
+		t.Errorf("a beats everything: %v", err)
+	}
+}
+
+func TestChooseOutcome(t *testing.T) {
+	for _, game := range []string{"rps", "rpsls"} {
+		r := mustRules(t, game)
+		for opp := range r.Shapes {
+			for _, want := range []Outcome{LOSE, DRAW, WIN} {
+				you, err := r.Choose(opp, want)
+				if err != nil {
+					t.Fatalf("%v: %v", game, err)
+				}
+				if got := r.Outcome(opp, you); got != want {
+					t.Errorf("%v: chose %v to %v against %v, but it will %v",
+						game, r.Shapes[you].Name, want, r.Shapes[opp].Name, got)
+				}
+			}
+		}
+	}
+
+	// Two shapes beat rock, Choose takes the one that scores more.
+	r := mustRules(t, "rpsls")
+	you, _ := r.Choose(0, WIN)
+	if r.Shapes[you].Name != "spock" {
+		t.Errorf("want spock to beat rock, got %v", r.Shapes[you].Name)
+	}
+}
+
+func TestScore(t *testing.T) {
+	r := mustRules(t, "rps")
+	for _, c := range []struct {
+		mode Mode
+		want int
+	}{
+		{SHAPE, 15},
+		{OUTCOME, 12},
+	} {
+		total := 0
+		for _, rd := range parseGuide(t, r, sampleGuide, c.mode) {
+			total += r.Score(rd)
+		}
+		if total != c.want {
+			t.Errorf("%v score got: %v want: %v", c.mode, total, c.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	r := mustRules(t, "rps")
+	for _, c := range []struct {
+		line string
+		mode Mode
+	}{
+		{"A", SHAPE},
+		{"A Y Z", SHAPE},
+		{"D Y", SHAPE},
+		{"A W", SHAPE},
+		{"A W", OUTCOME},
+		{"A Y", Mode("other")},
+	} {
+		if _, err := r.Parse(c.line, c.mode); err == nil {
+			t.Errorf("%q %v: expected an error", c.line, c.mode)
+		}
+	}
+}
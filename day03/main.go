@@ -1,99 +1,143 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"strings"
-)
 
-var (
-	priority = " abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/bitset"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/list"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
 )
 
-type Rucksack struct {
-	part1 string
-	part2 string
+var groupSize = flag.Int("group", 3, "how many elves share a badge")
+
+// Priority is a-z as 1-26 and A-Z as 27-52, it's also the item's bit in a set.
+func Priority(c byte) (int, error) {
+	switch {
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 1, nil
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 27, nil
+	}
+	return 0, fmt.Errorf("%q is not an item", c)
 }
 
-func New(line string) *Rucksack {
-	l := len(line)
-	h := l / 2
+// names turns a set of items back into letters.
+func names(items bitset.Bits64) string {
+	var b strings.Builder
+	items.Each(func(p int) {
+		if p <= 26 {
+			b.WriteByte(byte('a' + p - 1))
+		} else {
+			b.WriteByte(byte('A' + p - 27))
+		}
+	})
+	return b.String()
+}
 
-	return &Rucksack{
-		part1: line[0:h],
-		part2: line[h:l],
-	}
+// score adds up the priorities of all the items.
+func score(items bitset.Bits64) int {
+	return list.Sum(items.Indices())
 }
 
-func (r *Rucksack) FullIndex() map[string]struct{} {
-	idx := make(map[string]struct{})
-	for _, c := range r.part1 {
-		idx[string(c)] = struct{}{}
-	}
-	for _, c := range r.part2 {
-		idx[string(c)] = struct{}{}
-	}
-	return idx
+type Rucksack struct {
+	part1 bitset.Bits64
+	part2 bitset.Bits64
 }
 
-func (r *Rucksack) DupeScore() int {
-	p1m := make(map[string]struct{})
-	for _, c := range r.part1 {
-		p1m[string(c)] = struct{}{}
+func New(line string) (*Rucksack, error) {
+	l := len(line)
+	if l%2 != 0 {
+		return nil, fmt.Errorf("%v items don't split into two compartments", l)
 	}
+	h := l / 2
 
-	for _, c := range r.part2 {
-		dup := string(c)
-		if _, ok := p1m[dup]; ok {
-			return strings.Index(priority, dup)
+	r := &Rucksack{}
+	for i := 0; i < l; i++ {
+		p, err := Priority(line[i])
+		if err != nil {
+			return nil, &straid.ParseError{Text: line, Col: i + 1, Err: err}
+		}
+		if i < h {
+			r.part1 = r.part1.Set(p)
+		} else {
+			r.part2 = r.part2.Set(p)
 		}
 	}
-	return 0
+	return r, nil
 }
 
-func Intersection(r1, r2, r3 *Rucksack) int {
-	i1 := r1.FullIndex()
-	i2 := r2.FullIndex()
-
-	input := fmt.Sprintf("%v%v", r3.part1, r3.part2)
-	for _, c := range input {
-		char := string(c)
+func (r *Rucksack) FullIndex() bitset.Bits64 {
+	return r.part1.Or(r.part2)
+}
 
-		_, in1 := i1[char]
-		_, in2 := i2[char]
+// Shared is every item in both compartments.
+func (r *Rucksack) Shared() bitset.Bits64 {
+	return r.part1.And(r.part2)
+}
 
-		if in1 && in2 {
-			return strings.Index(priority, char)
-		}
+// Badge is the one item carried by everyone in the group.
+func Badge(group []*Rucksack) (int, error) {
+	common := bitset.Full(64)
+	for _, r := range group {
+		common = common.And(r.FullIndex())
 	}
-	return 0
+	switch common.Count() {
+	case 0:
+		return 0, fmt.Errorf("no badge, nothing is carried by all %v elves", len(group))
+	case 1:
+		return common.Indices()[0], nil
+	}
+	return 0, fmt.Errorf("more than one badge, all %v elves carry %v", len(group), names(common))
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
+	aoc.Parse()
+	size := *groupSize
+	if size < 1 {
+		log.Fatalf("-group must be at least 1, got %v", size)
+	}
+
+	reader := straid.NewLineReader("day03 input", os.Stdin)
+	reader.SkipBlank = true
 
 	tot := 0
-	part2 := 0
-	buf := make([]*Rucksack, 0)
-	for scanner.Scan() {
-		line := scanner.Text()
-		r := New(line)
-
-		log.Printf("%v%v = %v\n", r.part1, r.part2, r.DupeScore())
-		tot += r.DupeScore()
-
-		buf = append(buf, r)
-		if len(buf) == 3 {
-			part2 += Intersection(buf[0], buf[1], buf[2])
-			buf = make([]*Rucksack, 0)
+	rucksacks := make([]*Rucksack, 0)
+	lines := make([]int, 0)
+	for reader.Scan() {
+		r, err := New(reader.Text())
+		if err != nil {
+			log.Fatal(reader.Wrap(err))
 		}
+
+		shared := r.Shared()
+		log.Printf("%v = %v\n", names(shared), score(shared))
+		tot += score(shared)
+
+		rucksacks = append(rucksacks, r)
+		lines = append(lines, reader.Line())
 	}
 	log.Printf("part1 = %v", tot)
+
+	part2 := 0
+	for i, group := range list.Chunk(rucksacks, size) {
+		first := lines[i*size]
+		if len(group) != size {
+			log.Fatalf("day03 input:%v: last group only has %v of %v elves", first, len(group), size)
+		}
+		badge, err := Badge(group)
+		if err != nil {
+			log.Fatalf("day03 input:%v: group %v: %v", first, i+1, err)
+		}
+		part2 += badge
+	}
 	log.Printf("part2 = %v", part2)
 
-	if err := scanner.Err(); err != nil {
+	if err := reader.Err(); err != nil {
 		log.Println(err)
 	}
 }
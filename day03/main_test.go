@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/bitset"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
+)
+
+// sample is the list of rucksacks from the puzzle.
+var sample = []string{
+	"vJrwpWtwJgWrhcsFMMfFFhFp",
+	"jqHRNqRjqzjGDLGLrsFMfFZSrLrFZsSL",
+	"PmmdzqPrVvPwwTWBwg",
+	"wMqvLMZHhHMvwLHjbvcjnnSBnvTQFn",
+	"ttgJtRGJQctTZtZT",
+	"CrZsJsPPZsGzwwsLwLmpwMDw",
+}
+
+func mustRucksacks(t *testing.T, lines []string) []*Rucksack {
+	t.Helper()
+	out := make([]*Rucksack, len(lines))
+	for i, l := range lines {
+		r, err := New(l)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[i] = r
+	}
+	return out
+}
+
+func TestPriority(t *testing.T) {
+	for c, want := range map[byte]int{'a': 1, 'p': 16, 'z': 26, 'A': 27, 'L': 38, 'Z': 52} {
+		if got, err := Priority(c); err != nil || got != want {
+			t.Errorf("%q got: %v, %v want: %v", c, got, err, want)
+		}
+	}
+	for _, c := range []byte{'0', ' ', '[', '{'} {
+		if _, err := Priority(c); err == nil {
+			t.Errorf("%q: expected an error", c)
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	var items bitset.Bits64
+	for _, c := range []byte("Lpza") {
+		p, _ := Priority(c)
+		items = items.Set(p)
+	}
+	if got := names(items); got != "apzL" {
+		t.Errorf("got: %q want: %q", got, "apzL")
+	}
+}
+
+func TestShared(t *testing.T) {
+	want := []string{"p", "L", "P", "v", "t", "s"}
+	total := 0
+	for i, r := range mustRucksacks(t, sample) {
+		shared := r.Shared()
+		if got := names(shared); got != want[i] {
+			t.Errorf("%v: shared got: %q want: %q", sample[i], got, want[i])
+		}
+		total += score(shared)
+	}
+	if total != 157 {
+		t.Errorf("part 1 got: %v want: 157", total)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	if _, err := New("abc"); err == nil {
+		t.Errorf("odd number of items: expected an error")
+	}
+	_, err := New("abc1")
+	var pe *straid.ParseError
+	if !errors.As(err, &pe) || pe.Col != 4 {
+		t.Errorf("want a ParseError at column 4, got: %v", err)
+	}
+}
+
+func TestBadge(t *testing.T) {
+	rucksacks := mustRucksacks(t, sample)
+	total := 0
+	for i, want := range []byte{'r', 'Z'} {
+		badge, err := Badge(rucksacks[i*3 : i*3+3])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p, _ := Priority(want); badge != p {
+			t.Errorf("group %v badge got: %v want: %v (%q)", i+1, badge, p, want)
+		}
+		total += badge
+	}
+	if total != 70 {
+		t.Errorf("part 2 got: %v want: 70", total)
+	}
+
+	cases := []struct {
+		lines []string
+		want  string
+	}{
+		{[]string{"ab", "cd"}, "no badge"},
+		{[]string{"abAB", "baBA", "aBcb"}, "more than one badge, all 3 elves carry abB"},
+	}
+	for _, c := range cases {
+		_, err := Badge(mustRucksacks(t, c.lines))
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%v got: %v want an error with %q", c.lines, err, c.want)
+		}
+	}
+}
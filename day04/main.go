@@ -1,70 +1,157 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
 	"log"
 	"os"
-	"strconv"
-	"strings"
+	"sort"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/interval"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
 )
 
-type Range struct {
-	Begin int64
-	End   int64
-}
+var (
+	relations = flag.Bool("relations", false, "print how the two assignments on each line relate")
+	conflicts = flag.Bool("conflicts", false, "print every pair of elves, partners or not, with overlapping sections")
+)
 
-func New(in string) (*Range, error) {
-	parts := strings.Split(in, "-")
+type Range = interval.Interval[int64]
 
-	begin, err := strconv.ParseInt(parts[0], 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("cannot parse part 0: %w", err)
+// Parse reads a pair of assignments, like "2-4,6-8".
+func Parse(line string) (Range, Range, error) {
+	var r1, r2 Range
+	if err := straid.Scan("{}-{},{}-{}", line, &r1.Low, &r1.High, &r2.Low, &r2.High); err != nil {
+		return r1, r2, err
 	}
-	end, err := strconv.ParseInt(parts[1], 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("cannot parse part 1: %w", err)
+	for _, r := range []Range{r1, r2} {
+		if r.Empty() {
+			return r1, r2, fmt.Errorf("%v ends before it begins", r)
+		}
 	}
-	return &Range{
-		Begin: begin,
-		End:   end,
-	}, nil
+	return r1, r2, nil
+}
+
+// Relation is one of Allen's thirteen interval relations.
+type Relation int
+
+const (
+	BEFORE Relation = iota
+	MEETS
+	OVERLAPS
+	STARTS
+	DURING
+	FINISHES
+	EQUALS
+	FINISHED_BY
+	CONTAINS
+	STARTED_BY
+	OVERLAPPED_BY
+	MET_BY
+	AFTER
+)
+
+var relationNames = []string{
+	"before", "meets", "overlaps", "starts", "during", "finishes", "equals",
+	"finished by", "contains", "started by", "overlapped by", "met by", "after",
 }
 
-func (r *Range) Contains(o *Range) bool {
-	return r.Begin <= o.Begin && r.End >= o.End
+func (rel Relation) String() string {
+	return relationNames[rel]
 }
 
-func (r *Range) Overlaps(o *Range) bool {
-	// Order by beginning section, makes comparison easier.
-	r1 := r
-	r2 := o
-	if r2.Begin < r1.Begin {
-		r1, r2 = r2, r1
+// Relate classifies r against o. Sections are whole numbers, so 2-4 covers
+// [2, 5) and meets 5-6 with no section in common.
+func Relate(r, o Range) Relation {
+	b1, e1 := r.Low, r.High+1
+	b2, e2 := o.Low, o.High+1
+	switch {
+	case b1 == b2 && e1 == e2:
+		return EQUALS
+	case e1 < b2:
+		return BEFORE
+	case e1 == b2:
+		return MEETS
+	case e2 < b1:
+		return AFTER
+	case e2 == b1:
+		return MET_BY
+	case b1 == b2 && e1 < e2:
+		return STARTS
+	case b1 == b2:
+		return STARTED_BY
+	case e1 == e2 && b1 > b2:
+		return FINISHES
+	case e1 == e2:
+		return FINISHED_BY
+	case b1 > b2 && e1 < e2:
+		return DURING
+	case b1 < b2 && e1 > e2:
+		return CONTAINS
+	case b1 < b2:
+		return OVERLAPS
 	}
-	return r2.Begin <= r1.End
+	return OVERLAPPED_BY
 }
 
-func (r *Range) String() string {
-	return fmt.Sprintf("%v-%v", r.Begin, r.End)
+// Elf is an assignment with where it came from, elves 1 and 2 on a line.
+type Elf struct {
+	Line  int
+	Elf   int
+	Range Range
+}
+
+func (e *Elf) String() string {
+	return fmt.Sprintf("line %v elf %v (%v)", e.Line, e.Elf, e.Range)
+}
+
+// OverlappingPairs sweeps across the sections to find every pair of elves
+// that share a section, in order of the later one's start.
+func OverlappingPairs(elves []*Elf) [][2]*Elf {
+	sorted := append([]*Elf(nil), elves...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Range.Low < sorted[j].Range.Low
+	})
+
+	pairs := make([][2]*Elf, 0)
+	active := make([]*Elf, 0)
+	for _, e := range sorted {
+		// Drop anyone who finished before this elf starts.
+		keep := active[:0]
+		for _, a := range active {
+			if a.Range.High >= e.Range.Low {
+				keep = append(keep, a)
+			}
+		}
+		active = keep
+
+		for _, a := range active {
+			pairs = append(pairs, [2]*Elf{a, e})
+		}
+		active = append(active, e)
+	}
+	return pairs
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
+	aoc.Parse()
+
+	reader := straid.NewLineReader("day04 input", os.Stdin)
+	reader.SkipBlank = true
 
 	contains := 0
 	overlaps := 0
-	for scanner.Scan() {
-		line := scanner.Text()
-		parts := strings.Split(line, ",")
-		r1, err := New(parts[0])
+	elves := make([]*Elf, 0)
+	for reader.Scan() {
+		r1, r2, err := Parse(reader.Text())
 		if err != nil {
-			panic(err)
+			log.Fatal(reader.Wrap(err))
 		}
-		r2, err := New(parts[1])
-		if err != nil {
-			panic(err)
+		if *relations {
+			log.Printf("%v: %v %v %v", reader.Line(), r1, Relate(r1, r2), r2)
 		}
+		elves = append(elves, &Elf{reader.Line(), 1, r1}, &Elf{reader.Line(), 2, r2})
 
 		if r1.Contains(r2) || r2.Contains(r1) {
 			contains++
@@ -77,7 +164,15 @@ func main() {
 	log.Printf("Fully contains: %v\n", contains)
 	log.Printf("Overlaps: %v\n", overlaps)
 
-	if err := scanner.Err(); err != nil {
+	if *conflicts {
+		pairs := OverlappingPairs(elves)
+		for _, p := range pairs {
+			log.Printf("%v overlaps %v", p[0], p[1])
+		}
+		log.Printf("Overlapping pairs of elves: %v", len(pairs))
+	}
+
+	if err := reader.Err(); err != nil {
 		log.Println(err)
 	}
 }
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestRelate(t *testing.T) {
+	cases := []struct {
+		r, o string
+		want Relation
+	}{
+		{"2-3", "5-6", BEFORE},
+		{"2-4", "5-6", MEETS},
+		{"2-4", "4-6", OVERLAPS},
+		{"2-4", "2-6", STARTS},
+		{"3-4", "2-6", DURING},
+		{"4-6", "2-6", FINISHES},
+		{"2-6", "2-6", EQUALS},
+		{"2-6", "4-6", FINISHED_BY},
+		{"2-6", "3-4", CONTAINS},
+		{"2-6", "2-4", STARTED_BY},
+		{"4-6", "2-4", OVERLAPPED_BY},
+		{"5-6", "2-4", MET_BY},
+		{"5-6", "2-3", AFTER},
+
+		// Single sections.
+		{"3-3", "3-3", EQUALS},
+		{"3-3", "4-4", MEETS},
+		{"3-3", "5-5", BEFORE},
+		{"3-3", "3-5", STARTS},
+		{"3-3", "1-5", DURING},
+		{"5-5", "3-5", FINISHES},
+		{"3-4", "4-4", FINISHED_BY},
+	}
+	seen := make(map[Relation]bool)
+	for _, c := range cases {
+		r, o, err := Parse(c.r + "," + c.o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Relate(r, o); got != c.want {
+			t.Errorf("%v against %v got: %v want: %v", c.r, c.o, got, c.want)
+		}
+		// The relations are listed so that each one's converse is at the
+		// mirrored position.
+		if got, want := Relate(o, r), AFTER-c.want; got != want {
+			t.Errorf("%v against %v got: %v want: %v", c.o, c.r, got, want)
+		}
+		seen[c.want] = true
+	}
+	if len(seen) != len(relationNames) {
+		t.Errorf("only %v of %v relations tested", len(seen), len(relationNames))
+	}
+}
+
+// pairKey names a pair of elves the same way whichever order they're in.
+func pairKey(a, b *Elf) string {
+	if b.Line < a.Line || (b.Line == a.Line && b.Elf < a.Elf) {
+		a, b = b, a
+	}
+	return fmt.Sprintf("%v/%v-%v/%v", a.Line, a.Elf, b.Line, b.Elf)
+}
+
+func TestOverlappingPairs(t *testing.T) {
+	sample := []string{"2-4,6-8", "2-3,4-5", "5-7,7-9", "2-8,3-7", "6-6,4-6", "2-6,4-8"}
+	rnd := rand.New(rand.NewSource(4))
+	random := make([]string, 200)
+	for i := range random {
+		a, b := rnd.Int63n(90)+1, rnd.Int63n(90)+1
+		random[i] = fmt.Sprintf("%v-%v,%v-%v", a, a+rnd.Int63n(10), b, b+rnd.Int63n(10))
+	}
+
+	for _, lines := range [][]string{sample, random} {
+		elves := make([]*Elf, 0, 2*len(lines))
+		for i, l := range lines {
+			r1, r2, err := Parse(l)
+			if err != nil {
+				t.Fatal(err)
+			}
+			elves = append(elves, &Elf{Line: i + 1, Elf: 1, Range: r1}, &Elf{Line: i + 1, Elf: 2, Range: r2})
+		}
+
+		want := make(map[string]bool)
+		for i, a := range elves {
+			for _, b := range elves[i+1:] {
+				if a.Range.Low <= b.Range.High && b.Range.Low <= a.Range.High {
+					want[pairKey(a, b)] = true
+				}
+			}
+		}
+
+		pairs := OverlappingPairs(elves)
+		got := make(map[string]bool, len(pairs))
+		for _, p := range pairs {
+			k := pairKey(p[0], p[1])
+			if got[k] {
+				t.Errorf("%v found twice", k)
+			}
+			if !want[k] {
+				t.Errorf("%v don't overlap", k)
+			}
+			got[k] = true
+		}
+		if len(got) != len(want) {
+			t.Errorf("found %v pairs, want %v", len(got), len(want))
+		}
+	}
+}
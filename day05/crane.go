@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/collections"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/mathaid"
+)
+
+// Crane carries out a move on the stacks, the caller has already checked the
+// from stack has enough crates.
+type Crane interface {
+	Name() string
+	Move(d *Drawing, m Move)
+}
+
+// CrateMover9000 moves one crate at a time, so they end up reversed.
+type CrateMover9000 struct{}
+
+func (CrateMover9000) Name() string {
+	return "CrateMover 9000"
+}
+
+func (CrateMover9000) Move(d *Drawing, m Move) {
+	for i := 0; i < m.Amount; i++ {
+		d.Stacks[m.To].Push(d.Stacks[m.From].Pop())
+	}
+}
+
+// CrateMover9001 moves all the crates at once, keeping their order.
+type CrateMover9001 struct{}
+
+func (CrateMover9001) Name() string {
+	return "CrateMover 9001"
+}
+
+func (CrateMover9001) Move(d *Drawing, m Move) {
+	lift(d, m.From, m.To, m.Amount)
+}
+
+// MaxLift moves up to Lift crates at a time, keeping the order of each lift.
+type MaxLift struct {
+	Lift int
+}
+
+func (c MaxLift) Name() string {
+	return fmt.Sprintf("max lift %v", c.Lift)
+}
+
+func (c MaxLift) Move(d *Drawing, m Move) {
+	for left := m.Amount; left > 0; left -= c.Lift {
+		lift(d, m.From, m.To, mathaid.Min(left, c.Lift))
+	}
+}
+
+// lift moves the top n crates together.
+func lift(d *Drawing, from, to, n int) {
+	tmp := collections.NewStack[string]()
+	for i := 0; i < n; i++ {
+		tmp.Push(d.Stacks[from].Pop())
+	}
+	for !tmp.Empty() {
+		d.Stacks[to].Push(tmp.Pop())
+	}
+}
+
+// ParseCrane turns 9000, 9001 or lift:N into a Crane.
+func ParseCrane(s string) (Crane, error) {
+	switch s {
+	case "9000":
+		return CrateMover9000{}, nil
+	case "9001":
+		return CrateMover9001{}, nil
+	}
+	if strings.HasPrefix(s, "lift:") {
+		lift, err := strconv.Atoi(strings.TrimPrefix(s, "lift:"))
+		if err != nil || lift < 1 {
+			return nil, fmt.Errorf("crane %q: lift must be a positive number", s)
+		}
+		return MaxLift{Lift: lift}, nil
+	}
+	return nil, fmt.Errorf("unknown crane %q, want 9000, 9001 or lift:N", s)
+}
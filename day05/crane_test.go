@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCraneMove(t *testing.T) {
+	cases := []struct {
+		crane  Crane
+		amount int
+		from   string
+		to     string
+	}{
+		{CrateMover9000{}, 3, "[A]", "[D C B]"},
+		{CrateMover9001{}, 3, "[A]", "[B C D]"},
+		{MaxLift{Lift: 1}, 3, "[A]", "[D C B]"},
+		{MaxLift{Lift: 2}, 3, "[A]", "[C D B]"},
+		{MaxLift{Lift: 2}, 4, "[]", "[C D A B]"},
+		{MaxLift{Lift: 3}, 3, "[A]", "[B C D]"},
+		{MaxLift{Lift: 5}, 3, "[A]", "[B C D]"},
+		{MaxLift{Lift: 5}, 4, "[]", "[A B C D]"},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%v move %v", c.crane.Name(), c.amount), func(t *testing.T) {
+			// Stack 1 is A to D from the bottom, stack 2 starts empty.
+			d := parse(t, "[D]\n[C]\n[B]\n[A]\n 1   2\n")
+			m := Move{Amount: c.amount, From: 0, To: 1}
+			if err := d.Check(m); err != nil {
+				t.Fatal(err)
+			}
+			c.crane.Move(d, m)
+			if got := fmt.Sprint(d.Stacks[0].Values()); got != c.from {
+				t.Errorf("from stack got: %v want: %v", got, c.from)
+			}
+			if got := fmt.Sprint(d.Stacks[1].Values()); got != c.to {
+				t.Errorf("to stack got: %v want: %v", got, c.to)
+			}
+		})
+	}
+}
+
+func TestRenderRoundTrip(t *testing.T) {
+	d := parse(t, "        [QQQ]\n[GA]    [B]\n[BB] [Q] [C]\n  1  22  333\n")
+	CrateMover9001{}.Move(d, Move{Amount: 2, From: 2, To: 1})
+
+	out := d.Render()
+	back := parse(t, out)
+	if fmt.Sprint(back.Labels) != fmt.Sprint(d.Labels) {
+		t.Errorf("labels got: %v want: %v", back.Labels, d.Labels)
+	}
+	for i := range d.Stacks {
+		if got, want := fmt.Sprint(back.Stacks[i].Values()), fmt.Sprint(d.Stacks[i].Values()); got != want {
+			t.Errorf("stack %v got: %v want: %v", d.Labels[i], got, want)
+		}
+	}
+	if again := back.Render(); again != out {
+		t.Errorf("rendered differently the second time:\n%v\n%v", out, again)
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/collections"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/interval"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/mathaid"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
+)
+
+// token is a word from the drawing and the columns it covers, from 1.
+type token struct {
+	text string
+	cols interval.Interval[int]
+}
+
+// labelTokens splits the label row on spaces.
+func labelTokens(line string) []token {
+	toks := make([]token, 0)
+	for i := 0; i < len(line); {
+		if line[i] == ' ' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(line) && line[j] != ' ' {
+			j++
+		}
+		toks = append(toks, token{text: line[i:j], cols: interval.New(i+1, j)})
+		i = j
+	}
+	return toks
+}
+
+// crateTokens finds each [crate] in a row of the drawing, the text is the
+// crate's name without the brackets.
+func crateTokens(line string) ([]token, error) {
+	toks := make([]token, 0)
+	for i := 0; i < len(line); {
+		if line[i] == ' ' {
+			i++
+			continue
+		}
+		if line[i] != '[' {
+			return nil, &straid.ParseError{Text: line[i:], Col: i + 1, Err: fmt.Errorf("expected a crate like [A]")}
+		}
+		end := strings.IndexByte(line[i:], ']')
+		if end < 0 {
+			return nil, &straid.ParseError{Text: line[i:], Col: i + 1, Err: fmt.Errorf("crate is missing its ]")}
+		}
+		end += i
+		if end == i+1 {
+			return nil, &straid.ParseError{Text: line[i : end+1], Col: i + 1, Err: fmt.Errorf("crate has no name")}
+		}
+		toks = append(toks, token{text: line[i+1 : end], cols: interval.New(i+1, end+1)})
+		i = end + 1
+	}
+	return toks, nil
+}
+
+// Drawing is the stacks of crates, named by the labels under them.
+type Drawing struct {
+	Labels []string
+	Stacks []*collections.Stack[string]
+	index  map[string]int
+}
+
+// ParseDrawing reads the block with the crates, ending with the row of stack
+// labels. Each crate belongs to the label under it, so labels and crate names
+// can be any width, and trailing spaces don't matter.
+func ParseDrawing(b *straid.Block) (*Drawing, error) {
+	labelRow := b.Len() - 1
+	labels := labelTokens(b.Lines[labelRow])
+	if len(labels) == 0 {
+		return nil, b.Errorf(labelRow, "no stack labels under the crates")
+	}
+
+	d := &Drawing{
+		Labels: make([]string, len(labels)),
+		Stacks: make([]*collections.Stack[string], len(labels)),
+		index:  make(map[string]int, len(labels)),
+	}
+	for i, l := range labels {
+		if _, ok := d.index[l.text]; ok {
+			return nil, b.Wrap(labelRow, &straid.ParseError{Text: l.text, Col: l.cols.Low, Err: fmt.Errorf("stack label used twice")})
+		}
+		d.index[l.text] = i
+		d.Labels[i] = l.text
+		d.Stacks[i] = collections.NewStack[string]()
+	}
+
+	// Fill from the bottom up, a crate can't be above an empty spot.
+	gap := make([]bool, len(labels))
+	for l := labelRow - 1; l >= 0; l-- {
+		crates, err := crateTokens(b.Lines[l])
+		if err != nil {
+			return nil, b.Wrap(l, err)
+		}
+		filled := make([]bool, len(labels))
+		for _, c := range crates {
+			stack, err := stackFor(c, labels)
+			if err != nil {
+				return nil, b.Wrap(l, err)
+			}
+			if gap[stack] {
+				return nil, b.Wrap(l, &straid.ParseError{Text: c.text, Col: c.cols.Low, Err: fmt.Errorf("crate is floating above stack %v", labels[stack].text)})
+			}
+			filled[stack] = true
+			d.Stacks[stack].Push(c.text)
+		}
+		for i, f := range filled {
+			gap[i] = gap[i] || !f
+		}
+	}
+	return d, nil
+}
+
+// stackFor is the one label that's under crate c.
+func stackFor(c token, labels []token) (int, error) {
+	found := -1
+	for i, l := range labels {
+		if !c.cols.Overlaps(l.cols) {
+			continue
+		}
+		if found >= 0 {
+			return 0, &straid.ParseError{Text: c.text, Col: c.cols.Low,
+				Err: fmt.Errorf("crate is above both stack %v and %v", labels[found].text, l.text)}
+		}
+		found = i
+	}
+	if found < 0 {
+		return 0, &straid.ParseError{Text: c.text, Col: c.cols.Low, Err: fmt.Errorf("crate isn't above a stack label")}
+	}
+	return found, nil
+}
+
+func (d *Drawing) Clone() *Drawing {
+	c := &Drawing{
+		Labels: d.Labels,
+		Stacks: make([]*collections.Stack[string], len(d.Stacks)),
+		index:  d.index,
+	}
+	for i, s := range d.Stacks {
+		c.Stacks[i] = s.Clone()
+	}
+	return c
+}
+
+// Tops is the name of the top crate of each stack, empty stacks are skipped.
+func (d *Drawing) Tops() string {
+	var b strings.Builder
+	for _, s := range d.Stacks {
+		if !s.Empty() {
+			b.WriteString(s.Peek())
+		}
+	}
+	return b.String()
+}
+
+// Move is a line of the rearrangement procedure, with stacks as indexes.
+type Move struct {
+	Amount int
+	From   int
+	To     int
+
+	// block and line are where the move was read from, for errors.
+	block *straid.Block
+	line  int
+}
+
+// ParseMoves reads the procedure, checking the stacks exist. Whether there are
+// enough crates to move is only known when the moves are run.
+func (d *Drawing) ParseMoves(b *straid.Block) ([]Move, error) {
+	moves := make([]Move, 0, b.Len())
+	for l := range b.Lines {
+		var amount int
+		var from, to string
+		if err := b.Scan(l, "move {} from {} to {}", &amount, &from, &to); err != nil {
+			return nil, err
+		}
+		if amount < 1 {
+			return nil, b.Errorf(l, "can't move %v crates", amount)
+		}
+		m := Move{Amount: amount, block: b, line: l}
+		var ok bool
+		if m.From, ok = d.index[from]; !ok {
+			return nil, b.Errorf(l, "no stack %q, the stacks are %v", from, strings.Join(d.Labels, " "))
+		}
+		if m.To, ok = d.index[to]; !ok {
+			return nil, b.Errorf(l, "no stack %q, the stacks are %v", to, strings.Join(d.Labels, " "))
+		}
+		moves = append(moves, m)
+	}
+	return moves, nil
+}
+
+// Check returns an error if the from stack doesn't have enough crates for m,
+// pointing at the input line if m came from ParseMoves.
+func (d *Drawing) Check(m Move) error {
+	have := d.Stacks[m.From].Len()
+	if have >= m.Amount {
+		return nil
+	}
+	err := fmt.Errorf("move %v from %v: stack only has %v crates", m.Amount, d.Labels[m.From], have)
+	if m.block == nil {
+		return err
+	}
+	return m.block.Wrap(m.line, err)
+}
+
+// Render draws the stacks the same way as the input, so it can be parsed back.
+func (d *Drawing) Render() string {
+	height := 0
+	crates := make([][]string, len(d.Stacks))
+	width := make([]int, len(d.Stacks))
+	for i, s := range d.Stacks {
+		crates[i] = s.Values()
+		height = mathaid.Max(height, len(crates[i]))
+		width[i] = mathaid.Max(len(d.Labels[i])+1, 3)
+		for _, c := range crates[i] {
+			width[i] = mathaid.Max(width[i], len(c)+2)
+		}
+	}
+
+	var b strings.Builder
+	row := make([]string, len(d.Stacks))
+	for h := height - 1; h >= 0; h-- {
+		for i, c := range crates {
+			cell := ""
+			if h < len(c) {
+				cell = "[" + c[h] + "]"
+			}
+			row[i] = fmt.Sprintf("%-*s", width[i], cell)
+		}
+		b.WriteString(strings.TrimRight(strings.Join(row, " "), " "))
+		b.WriteString("\n")
+	}
+	for i, l := range d.Labels {
+		row[i] = fmt.Sprintf(" %-*s", width[i]-1, l)
+	}
+	b.WriteString(strings.TrimRight(strings.Join(row, " "), " "))
+	b.WriteString("\n")
+	return b.String()
+}
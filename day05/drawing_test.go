@@ -0,0 +1,163 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
+)
+
+// blocks splits input the same way main does.
+func blocks(t *testing.T, input string) []*straid.Block {
+	t.Helper()
+	r := straid.NewBlockReader("test", strings.NewReader(input))
+	out := make([]*straid.Block, 0)
+	for r.Scan() {
+		out = append(out, r.Block())
+	}
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func parse(t *testing.T, drawing string) *Drawing {
+	t.Helper()
+	d, err := ParseDrawing(blocks(t, drawing)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestParseDrawing(t *testing.T) {
+	cases := []struct {
+		name    string
+		drawing string
+		labels  string
+		tops    string
+		heights []int
+	}{
+		{
+			name:    "sample",
+			drawing: "    [D]    \n[N] [C]    \n[Z] [M] [P]\n 1   2   3 \n",
+			labels:  "1 2 3",
+			tops:    "NDP",
+			heights: []int{2, 3, 1},
+		},
+		{
+			name:    "trimmed trailing spaces",
+			drawing: "    [D]\n[N] [C]\n[Z] [M] [P]\n 1   2   3\n",
+			labels:  "1 2 3",
+			tops:    "NDP",
+			heights: []int{2, 3, 1},
+		},
+		{
+			name: "multi-digit labels",
+			drawing: "                                    [J]\n" +
+				"[A] [B] [C] [D] [E] [F] [G] [H] [I] [X] [K]\n" +
+				" 1   2   3   4   5   6   7   8   9   10  11\n",
+			labels:  "1 2 3 4 5 6 7 8 9 10 11",
+			tops:    "ABCDEFGHIJK",
+			heights: []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 2, 1},
+		},
+		{
+			name:    "multi-character crates",
+			drawing: "[GA]\n[BB] [Q]\n  1   2\n",
+			labels:  "1 2",
+			tops:    "GAQ",
+			heights: []int{2, 1},
+		},
+		{
+			name:    "empty stack",
+			drawing: "[A]     [C]\n 1   2   3\n",
+			labels:  "1 2 3",
+			tops:    "AC",
+			heights: []int{1, 0, 1},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := parse(t, c.drawing)
+			if got := strings.Join(d.Labels, " "); got != c.labels {
+				t.Errorf("labels got: %q want: %q", got, c.labels)
+			}
+			if got := d.Tops(); got != c.tops {
+				t.Errorf("tops got: %q want: %q", got, c.tops)
+			}
+			for i, h := range c.heights {
+				if got := d.Stacks[i].Len(); got != h {
+					t.Errorf("stack %v has %v crates, want %v", d.Labels[i], got, h)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDrawingErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		drawing string
+		want    string
+	}{
+		{"floating crate", "[A] [B]\n[C]\n 1   2\n", `test:1:5: "B": crate is floating above stack 2`},
+		{"not above a label", "[A]     [B]\n 1   2\n", `test:1:9: "B": crate isn't above a stack label`},
+		{"above two labels", "[AAAAA]\n 1   2\n", `test:1:1: "AAAAA": crate is above both stack 1 and 2`},
+		{"label used twice", "[A] [B]\n 1   1\n", `test:2:6: "1": stack label used twice`},
+		{"not a crate", "[A] B\n 1   2\n", `test:1:5: "B": expected a crate like [A]`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ParseDrawing(blocks(t, c.drawing)[0])
+			if err == nil || err.Error() != c.want {
+				t.Errorf("got: %v want: %v", err, c.want)
+			}
+		})
+	}
+}
+
+func TestParseMoves(t *testing.T) {
+	cases := []struct {
+		name  string
+		moves string
+		want  string
+	}{
+		{"unknown from", "move 1 from 1 to 2\nmove 1 from 4 to 1\n", `test:7: no stack "4", the stacks are 1 2 3`},
+		{"unknown to", "move 1 from 1 to 0\n", `test:6: no stack "0", the stacks are 1 2 3`},
+		{"no crates", "move 0 from 1 to 2\n", `test:6: can't move 0 crates`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := blocks(t, "    [D]\n[N] [C]\n[Z] [M] [P]\n 1   2   3\n\n"+c.moves)
+			d := parse(t, strings.Join(b[0].Lines, "\n"))
+			_, err := d.ParseMoves(b[1])
+			if err == nil || err.Error() != c.want {
+				t.Errorf("got: %v want: %v", err, c.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	b := blocks(t, "    [D]\n[N] [C]\n[Z] [M] [P]\n 1   2   3\n\nmove 1 from 2 to 1\nmove 3 from 1 to 3\nmove 3 from 2 to 1\n")
+	d, err := ParseDrawing(b[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves, err := d.ParseMoves(b[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stack 2 is down to 2 crates after the first two moves.
+	for _, m := range moves[:2] {
+		if err := d.Check(m); err != nil {
+			t.Fatal(err)
+		}
+		CrateMover9000{}.Move(d, m)
+	}
+	want := "test:8: move 3 from 2: stack only has 2 crates"
+	if err := d.Check(moves[2]); err == nil || err.Error() != want {
+		t.Errorf("got: %v want: %v", err, want)
+	}
+}
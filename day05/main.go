@@ -1,126 +1,120 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
 	"log"
 	"os"
-	"strconv"
+	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
 )
 
-type Stack struct {
-	data []string
-}
+var (
+	cranes  = flag.String("cranes", "9000,9001", "comma separated cranes to run: 9000, 9001 or lift:N")
+	animate = flag.Duration("animate", 0, "redraw the stacks in the terminal after each move, waiting this long between moves, stdout must be a terminal so use -frames under aoc run")
+	frames  = flag.String("frames", "", "write the stacks after each move into this directory, one file per crane and move")
+)
 
-func New() *Stack {
-	return &Stack{
-		data: make([]string, 0),
+// run does all the moves with crane, on a copy of the stacks.
+func run(crane Crane, start *Drawing, moves []Move) (*Drawing, error) {
+	stacks := start.Clone()
+	if err := frame(crane, stacks, 0, "start"); err != nil {
+		return nil, err
 	}
-}
+	for i, m := range moves {
+		if err := stacks.Check(m); err != nil {
+			return nil, err
+		}
+		crane.Move(stacks, m)
 
-func (s *Stack) Clone() *Stack {
-	data := make([]string, len(s.data))
-	copy(data, s.data)
-	return &Stack{
-		data: data,
+		desc := fmt.Sprintf("move %v from %v to %v", m.Amount, stacks.Labels[m.From], stacks.Labels[m.To])
+		if err := frame(crane, stacks, i+1, desc); err != nil {
+			return nil, err
+		}
 	}
+	return stacks, nil
 }
 
-func (s *Stack) Peek() string {
-	return string(s.data[len(s.data)-1])
-}
-
-func (s *Stack) Empty() bool {
-	return len(s.data) == 0
-}
-
-func (s *Stack) Push(str string) {
-	s.data = append(s.data, str)
+// frame shows the stacks after move n, if asked for on the command line.
+func frame(crane Crane, stacks *Drawing, n int, desc string) error {
+	if *animate > 0 {
+		// Clear the screen and draw from the top.
+		fmt.Printf("\033[H\033[2J%v, %v: %v\n\n%v", crane.Name(), n, desc, stacks.Render())
+		time.Sleep(*animate)
+	}
+	if *frames != "" {
+		name := fmt.Sprintf("%s-%04d.txt", strings.ReplaceAll(crane.Name(), " ", "-"), n)
+		return os.WriteFile(filepath.Join(*frames, name), []byte(stacks.Render()), 0o644)
+	}
+	return nil
 }
 
-func (s *Stack) Pop() string {
-	r := s.data[len(s.data)-1]
-	s.data = s.data[0 : len(s.data)-1]
-	return r
+// label is what the answer is logged as, the puzzle's two parts keep their
+// usual names and any other crane is named after itself.
+func label(crane Crane) string {
+	switch crane.(type) {
+	case CrateMover9000:
+		return "part1"
+	case CrateMover9001:
+		return "part2"
+	}
+	return crane.Name()
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-
-	init := New()
-	numStacks := 0
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, " 1") {
-			p := strings.Fields(line)
-			numStacks = len(p)
-			break
+	aoc.Parse()
+	models := make([]Crane, 0)
+	for _, c := range strings.Split(*cranes, ",") {
+		crane, err := ParseCrane(c)
+		if err != nil {
+			log.Fatal(err)
 		}
-		init.Push(line)
+		models = append(models, crane)
 	}
-	scanner.Scan()
-	scanner.Text() // consume empty line
-
-	stacks := make([]Stack, numStacks)
-	for !init.Empty() {
-		line := init.Pop()
-		for i := 1; i <= numStacks; i++ {
-			pos := (i-1)*4 + 1
-			if s := string(line[pos]); s != " " {
-				stacks[i-1].Push(s)
-			}
-		}
-	}
-
-	// For solving p1 and p2 at the same time, deep copy the data.
-	p2stacks := make([]*Stack, numStacks)
-	for i, s := range stacks {
-		p2stacks[i] = s.Clone()
+	// aoc run buffers stdout, the escape codes would all come out at the end.
+	if *animate > 0 && !aoc.IsTerminal(os.Stdout) {
+		log.Fatal("-animate needs stdout to be a terminal, use -frames to keep each move instead")
 	}
-
-	for scanner.Scan() {
-		command := scanner.Text()
-		parts := strings.Fields(command)
-
-		amount, _ := strconv.ParseInt(parts[1], 10, 64)
-		from, _ := strconv.ParseInt(parts[3], 10, 64)
-		to, _ := strconv.ParseInt(parts[5], 10, 64)
-
-		// Part 1
-		for i := 0; i < int(amount); i++ {
-			val := stacks[from-1].Pop()
-			stacks[to-1].Push(val)
+	if *frames != "" {
+		if err := os.MkdirAll(*frames, 0o755); err != nil {
+			log.Fatal(err)
 		}
+	}
 
-		// Part 2
-		tmp := New()
-		for i := 0; i < int(amount); i++ {
-			tmp.Push(p2stacks[from-1].Pop())
-		}
-		for !tmp.Empty() {
-			p2stacks[to-1].Push(tmp.Pop())
-		}
+	reader := straid.NewBlockReader("day05 input", os.Stdin)
 
-		//log.Printf("command: %v\n", command)
-		//for _, s := range stacks {
-		//	fmt.Printf("%+v\n", s)
-		//}
+	// First block is the drawing, ending with the stack labels.
+	if !reader.Scan() {
+		log.Fatalf("day05 input: missing crate drawing")
+	}
+	stacks, err := ParseDrawing(reader.Block())
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	part1 := ""
-	for _, s := range stacks {
-		part1 = fmt.Sprintf("%s%s", part1, s.Peek())
+	// Second block is the moves.
+	if !reader.Scan() {
+		log.Fatalf("day05 input: missing moves after the crate drawing")
+	}
+	moves, err := stacks.ParseMoves(reader.Block())
+	if err != nil {
+		log.Fatal(err)
 	}
-	log.Printf("part1: %v\n", part1)
 
-	part2 := ""
-	for _, s := range p2stacks {
-		part2 = fmt.Sprintf("%s%s", part2, s.Peek())
+	// Part 1 is the 9000 and part 2 the 9001, each crane works on its own copy.
+	for _, crane := range models {
+		end, err := run(crane, stacks, moves)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("%v: %v\n", label(crane), end.Tops())
 	}
-	log.Printf("part2: %v\n", part2)
 
-	if err := scanner.Err(); err != nil {
+	if err := reader.Err(); err != nil {
 		log.Println(err)
 	}
 }
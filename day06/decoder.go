@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"io"
+)
+
+const (
+	PacketSize  = 4
+	MessageSize = 14
+)
+
+// Message is the data after a start-of-message marker, up to the next marker
+// or the end of the stream.
+type Message struct {
+	// Start is the position just after the marker, which is the puzzle answer
+	// for the first message.
+	Start int
+	Data  []byte
+}
+
+// readByte reads the next byte of a stream. A CRLF line ending is read as
+// just the '\n' and a '\r' at EOF is dropped, so whatever the line endings a
+// stream ends at a '\n' or io.EOF without the '\r' counting as part of it.
+func readByte(in *bufio.Reader) (byte, error) {
+	b, err := in.ReadByte()
+	if err == nil && b == '\r' {
+		if next, perr := in.Peek(1); perr == io.EOF || (perr == nil && next[0] == '\n') {
+			return in.ReadByte()
+		}
+	}
+	return b, err
+}
+
+// Decoder splits a datastream into messages. Anything before the first
+// start-of-message marker is dropped, and markers don't overlap, each one
+// needs MessageSize new bytes. The stream ends at EOF or a newline, like the
+// puzzle input.
+type Decoder struct {
+	in      *bufio.Reader
+	packet  *Detector
+	message *Detector
+	// packetStart is the position after the start-of-packet marker.
+	packetStart int
+	// base is where message started counting from, after the last marker.
+	base int
+
+	// cur is the message being read, nil until the first marker.
+	cur  *Message
+	buf  []byte
+	next *Message
+	more bool
+	done bool
+	err  error
+}
+
+// NewDecoder reads from r, which can be shared by several decoders in turn
+// if it's a *bufio.Reader.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		in:          bufio.NewReader(r),
+		packet:      newDetector(PacketSize),
+		message:     newDetector(MessageSize),
+		packetStart: -1,
+	}
+}
+
+// Next reads up to the end of the next message, and returns false when there
+// are no more or there was an error.
+func (d *Decoder) Next() bool {
+	d.next = nil
+	for !d.done {
+		b, err := readByte(d.in)
+		if err != nil || b == '\n' {
+			if err != io.EOF {
+				d.err = err
+			}
+			d.more = err == nil
+			d.done = true
+			// Whatever is left is the last message.
+			if d.cur != nil {
+				d.cur.Data = d.buf
+				d.next = d.cur
+			}
+			break
+		}
+
+		if d.packet.Push(b) && d.packetStart < 0 {
+			d.packetStart = d.packet.Pos()
+		}
+		d.buf = append(d.buf, b)
+		if !d.message.Push(b) {
+			continue
+		}
+
+		// The marker is the end of buf, which finishes the current message.
+		pos := d.base + d.message.Pos()
+		if d.cur != nil {
+			d.cur.Data = d.buf[:len(d.buf)-MessageSize]
+			d.next = d.cur
+		}
+		d.cur = &Message{Start: pos}
+		d.buf = nil
+		d.message.Reset()
+		d.base = pos
+		if d.next != nil {
+			return true
+		}
+	}
+	return d.next != nil
+}
+
+// Message is the message found by the last call to Next, or the zero Message
+// if Next returned false.
+func (d *Decoder) Message() Message {
+	if d.next == nil {
+		return Message{}
+	}
+	return *d.next
+}
+
+// PacketStart is the position after the start-of-packet marker, or -1 if it
+// hasn't been seen.
+func (d *Decoder) PacketStart() int {
+	return d.packetStart
+}
+
+// Pos is how many bytes of the stream have been read.
+func (d *Decoder) Pos() int {
+	return d.packet.Pos()
+}
+
+// More is true if the stream ended at a newline, so there may be another.
+func (d *Decoder) More() bool {
+	return d.more
+}
+
+func (d *Decoder) Err() error {
+	return d.err
+}
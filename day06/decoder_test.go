@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	for _, s := range samples {
+		dec := NewDecoder(strings.NewReader(s.stream + "\n"))
+		if !dec.Next() {
+			t.Fatalf("%v: no message, err: %v", s.stream, dec.Err())
+		}
+		m := dec.Message()
+		if m.Start != s.message || string(m.Data) != s.stream[s.message:] {
+			t.Errorf("%v: got message %+q at %v", s.stream, m.Data, m.Start)
+		}
+		if dec.Next() {
+			t.Errorf("%v: unexpected message %+q", s.stream, dec.Message().Data)
+		}
+		if dec.PacketStart() != s.packet {
+			t.Errorf("%v: packet got: %v want: %v", s.stream, dec.PacketStart(), s.packet)
+		}
+		if !dec.More() {
+			t.Errorf("%v: stream ended at a newline, should be more", s.stream)
+		}
+	}
+}
+
+func TestDecoderMessages(t *testing.T) {
+	// A second marker after the first sample, then a short message.
+	stream := samples[0].stream + "aaaa" + "abcdefghijklmn" + "zz"
+	dec := NewDecoder(strings.NewReader(stream))
+
+	want := []Message{
+		{Start: 19, Data: []byte("jfqwrcgsmlbaaaa")},
+		{Start: 48, Data: []byte("zz")},
+	}
+	for i, w := range want {
+		if !dec.Next() {
+			t.Fatalf("missing message %v, err: %v", i, dec.Err())
+		}
+		if m := dec.Message(); m.Start != w.Start || string(m.Data) != string(w.Data) {
+			t.Errorf("message %v got: %v %q want: %v %q", i, m.Start, m.Data, w.Start, w.Data)
+		}
+	}
+	if dec.Next() {
+		t.Errorf("unexpected message %q", dec.Message().Data)
+	}
+	if dec.More() {
+		t.Errorf("stream ended at EOF, shouldn't be more")
+	}
+}
+
+func TestDecoderSharedReader(t *testing.T) {
+	lines := make([]string, len(samples))
+	for i, s := range samples {
+		lines[i] = s.stream
+	}
+	in := bufio.NewReader(strings.NewReader(strings.Join(lines, "\n")))
+	for i := 0; ; i++ {
+		dec := NewDecoder(in)
+		for dec.Next() {
+			if got := dec.Message().Start; got != samples[i].message {
+				t.Errorf("stream %v message got: %v want: %v", i, got, samples[i].message)
+			}
+		}
+		if !dec.More() {
+			if i != len(samples)-1 {
+				t.Errorf("stopped after %v streams", i+1)
+			}
+			break
+		}
+	}
+}
+
+func TestDecoderCRLF(t *testing.T) {
+	s := samples[0]
+	for _, end := range []string{"\r\n", "\r"} {
+		dec := NewDecoder(strings.NewReader(s.stream + end))
+		if !dec.Next() {
+			t.Fatalf("%q: no message, err: %v", end, dec.Err())
+		}
+		if m := dec.Message(); string(m.Data) != s.stream[s.message:] {
+			t.Errorf("%q: got message %q", end, m.Data)
+		}
+		if dec.Pos() != len(s.stream) {
+			t.Errorf("%q: read %v bytes, want %v", end, dec.Pos(), len(s.stream))
+		}
+		if dec.More() != (end == "\r\n") {
+			t.Errorf("%q: wrong More: %v", end, dec.More())
+		}
+	}
+}
+
+func TestReadByte(t *testing.T) {
+	// The bytes read up to EOF, with "|" for each '\n'.
+	cases := map[string]string{
+		"ab\ncd":     "ab|cd",
+		"ab\r\ncd":   "ab|cd",
+		"ab\r":       "ab",
+		"a\rb\r\r\n": "a\rb\r|",
+	}
+	for input, want := range cases {
+		in := bufio.NewReader(strings.NewReader(input))
+		var got strings.Builder
+		for {
+			b, err := readByte(in)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b == '\n' {
+				b = '|'
+			}
+			got.WriteByte(b)
+		}
+		if got.String() != want {
+			t.Errorf("%q got: %q want: %q", input, got.String(), want)
+		}
+	}
+}
+
+func TestDecoderMessageAfterEnd(t *testing.T) {
+	dec := NewDecoder(strings.NewReader("aaaa"))
+	if dec.Next() {
+		t.Fatalf("unexpected message %q", dec.Message().Data)
+	}
+	if m := dec.Message(); m.Start != 0 || m.Data != nil {
+		t.Errorf("got: %+v want the zero Message", m)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import "fmt"
+
+// Detector watches a stream a byte at a time for markers, a run of Size bytes
+// that are all different. Counts are updated for the byte coming into the
+// window and the one leaving it, so each byte is O(1) whatever the size.
+type Detector struct {
+	Size int
+
+	counts [256]int
+	window []byte
+	// pos is how many bytes have been pushed.
+	pos int
+	// dupes is how many byte values are in the window more than once.
+	dupes int
+}
+
+// NewDetector looks for markers of size bytes, which must be at least 1.
+func NewDetector(size int) (*Detector, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("marker length must be at least 1, got %v", size)
+	}
+	return newDetector(size), nil
+}
+
+// newDetector is NewDetector for sizes known to be valid, like PacketSize.
+func newDetector(size int) *Detector {
+	return &Detector{
+		Size:   size,
+		window: make([]byte, size),
+	}
+}
+
+// Push adds the next byte, and returns true if the last Size bytes are a marker.
+func (d *Detector) Push(b byte) bool {
+	slot := d.pos % d.Size
+	if d.pos >= d.Size {
+		old := d.window[slot]
+		d.counts[old]--
+		if d.counts[old] == 1 {
+			d.dupes--
+		}
+	}
+	d.window[slot] = b
+	d.counts[b]++
+	if d.counts[b] == 2 {
+		d.dupes++
+	}
+	d.pos++
+	return d.pos >= d.Size && d.dupes == 0
+}
+
+// Pos is the number of bytes pushed, after a marker it's the marker's position.
+func (d *Detector) Pos() int {
+	return d.pos
+}
+
+// Reset starts a new stream.
+func (d *Detector) Reset() {
+	d.counts = [256]int{}
+	d.pos = 0
+	d.dupes = 0
+}
+
+// Marker returns the position after the first run of size distinct characters,
+// or -1 if there isn't one.
+func Marker(line string, size int) (int, error) {
+	d, err := NewDetector(size)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(line); i++ {
+		if d.Push(line[i]) {
+			return d.Pos(), nil
+		}
+	}
+	return -1, nil
+}
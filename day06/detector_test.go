@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// The sample datastreams from the puzzle, with the packet and message answers.
+var samples = []struct {
+	stream  string
+	packet  int
+	message int
+}{
+	{"mjqjpqmgbljsphdztnvjfqwrcgsmlb", 7, 19},
+	{"bvwbjplbgvbhsrlpgdmjqwftvncz", 5, 23},
+	{"nppdvjthqldpwncqszvftbrmjlhg", 6, 23},
+	{"nznrnfrfntjfmvfwmzdfjlvtqnbhcprsg", 10, 29},
+	{"zcfzfwzzqfrljwzlrfnpqdbhtmscgvjw", 11, 26},
+}
+
+func TestMarker(t *testing.T) {
+	for _, s := range samples {
+		if got, err := Marker(s.stream, PacketSize); err != nil || got != s.packet {
+			t.Errorf("%v packet got: %v, %v want: %v", s.stream, got, err, s.packet)
+		}
+		if got, err := Marker(s.stream, MessageSize); err != nil || got != s.message {
+			t.Errorf("%v message got: %v, %v want: %v", s.stream, got, err, s.message)
+		}
+	}
+	if got, err := Marker("aabbaabb", 3); err != nil || got != -1 {
+		t.Errorf("found a marker that isn't there at %v, %v", got, err)
+	}
+	for _, size := range []int{0, -1} {
+		if _, err := Marker("abcd", size); err == nil {
+			t.Errorf("size %v: expected an error", size)
+		}
+	}
+}
+
+func TestDetectorAll(t *testing.T) {
+	d, err := NewDetector(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make([]int, 0)
+	for _, b := range []byte("abcabbxyz") {
+		if d.Push(b) {
+			found = append(found, d.Pos())
+		}
+	}
+	if got := fmt.Sprint(found); got != "[3 4 5 8 9]" {
+		t.Errorf("wrong markers, got: %v", got)
+	}
+}
@@ -2,76 +2,113 @@ package main
 
 import (
 	"bufio"
+	"flag"
+	"io"
 	"log"
 	"os"
-)
+	"strconv"
+	"strings"
 
-// Ring is a simple string ring buffer.
-type Ring struct {
-	buffer []string
-	size   int
-	pos    int
-}
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
+)
 
-func New(size int) *Ring {
-	return &Ring{
-		buffer: make([]string, size),
-		size:   0,
-		pos:    0,
-	}
-}
+var (
+	sizes    = flag.String("sizes", "4,14", "comma separated marker lengths to look for")
+	all      = flag.Bool("all", false, "report every marker position, not just the first")
+	messages = flag.Bool("messages", false, "split each stream into its messages instead")
+)
 
-func (r *Ring) Append(s string) {
-	r.buffer[r.pos] = s
-	if r.size < len(r.buffer) {
-		r.size++
-	}
-	r.pos++
-	if r.pos == len(r.buffer) {
-		r.pos = 0
-	}
+// result is what one detector found in a stream.
+type result struct {
+	first int
+	all   []int
 }
 
-func (r *Ring) PacketStart() bool {
-	if r.size < len(r.buffer) {
-		return false
-	}
-	m := make(map[string]struct{})
-	for _, v := range r.buffer {
-		m[v] = struct{}{}
+// decode prints the messages in each stream.
+func decode(in *bufio.Reader) {
+	for stream := 1; ; stream++ {
+		dec := NewDecoder(in)
+		for dec.Next() {
+			m := dec.Message()
+			log.Printf("stream %v, message at %v: %q\n", stream, m.Start, m.Data)
+		}
+		if err := dec.Err(); err != nil {
+			log.Fatal(err)
+		}
+		if dec.Pos() > 0 {
+			log.Printf("stream %v, packet at %v\n", stream, dec.PacketStart())
+		}
+		if !dec.More() {
+			return
+		}
 	}
-	return len(m) == len(r.buffer)
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	line := scanner.Text()
-
-	r1 := New(4)
-	r2 := New(14)
-
-	r1done, r2done := false, false
-
-	for i := 0; i < len(line); i++ {
-		r1.Append(string(line[i]))
-		r2.Append(string(line[i]))
-
-		if !r1done && r1.PacketStart() {
-			log.Printf("packet: %v\n", i+1)
-			r1done = true
+	aoc.Parse()
+	detectors := make([]*Detector, 0)
+	for _, s := range strings.Split(*sizes, ",") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			log.Fatalf("marker length %q must be a positive number", s)
+		}
+		d, err := NewDetector(n)
+		if err != nil {
+			log.Fatal(err)
 		}
+		detectors = append(detectors, d)
+	}
 
-		if !r2done && r2.PacketStart() {
-			log.Printf("message: %v\n", i+1)
-			r2done = true
+	// Input is read a byte at a time, so a stream can be any length. Each line
+	// is a separate stream, ending the same way as for the Decoder.
+	in := bufio.NewReader(os.Stdin)
+	if *messages {
+		decode(in)
+		return
+	}
+	stream := 0
+	results := make([]result, len(detectors))
+	report := func() {
+		if detectors[0].Pos() == 0 {
+			return
 		}
-		if r1done && r2done {
-			break
+		stream++
+		for i, d := range detectors {
+			log.Printf("stream %v, %v distinct: %v\n", stream, d.Size, results[i].first)
+			if *all {
+				log.Printf("stream %v, %v distinct, all: %v\n", stream, d.Size, results[i].all)
+			}
+			d.Reset()
+			results[i] = result{first: -1}
 		}
 	}
+	for i := range results {
+		results[i].first = -1
+	}
 
-	if err := scanner.Err(); err != nil {
-		log.Println(err)
+	for {
+		b, err := readByte(in)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if b == '\n' {
+			report()
+			continue
+		}
+		for i, d := range detectors {
+			if !d.Push(b) {
+				continue
+			}
+			if results[i].first < 0 {
+				results[i].first = d.Pos()
+			}
+			if *all {
+				results[i].all = append(results[i].all, d.Pos())
+			}
+		}
 	}
+	report()
 }
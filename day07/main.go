@@ -7,6 +7,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/memo"
 )
 
 type fstype int
@@ -22,10 +24,20 @@ type Node struct {
 	Size     int64
 	Parent   *Node
 	Children []*Node
-
-	totalSize int64
 }
 
+// Directory sizes are cached, adding a child invalidates its ancestors.
+var totalSizes = memo.NewRecursive(func(self func(*Node) int64, n *Node) int64 {
+	if n.FSType == FILE {
+		return n.Size
+	}
+	sum := int64(0)
+	for _, c := range n.Children {
+		sum += self(c)
+	}
+	return sum
+})
+
 func NewNode(name string, typ fstype, size int64, parent *Node) *Node {
 	return &Node{
 		Name:     name,
@@ -39,24 +51,14 @@ func NewNode(name string, typ fstype, size int64, parent *Node) *Node {
 func (n *Node) AddChild(name string, typ fstype, size int64) *Node {
 	child := NewNode(name, typ, size, n)
 	n.Children = append(n.Children, child)
+	for p := n; p != nil; p = p.Parent {
+		totalSizes.Invalidate(p)
+	}
 	return child
 }
 
 func (n *Node) TotalSize() int64 {
-	if n.FSType == FILE {
-		return n.Size
-	}
-	if n.totalSize > 0 {
-		return n.totalSize
-	}
-
-	sum := int64(0)
-	for _, c := range n.Children {
-		sum += c.TotalSize()
-	}
-	// memoize because the tree currently can't change.
-	n.totalSize = sum
-	return sum
+	return totalSizes.Get(n)
 }
 
 func (n *Node) Print(depth int) {
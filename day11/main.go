@@ -1,14 +1,14 @@
 package main
 
 import (
-	"bufio"
-	"errors"
 	"fmt"
 	"log"
 	"os"
 	"sort"
-	"strconv"
-	"strings"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/mathaid"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
 )
 
 type WorryFunc func(a int64) int64
@@ -79,128 +79,108 @@ type Route struct {
 	Item   int64
 }
 
-var errorDone = errors.New("done")
-
-func NewMonkey(scanner *bufio.Scanner) (*Monkey, error) {
-	scanner.Scan()
-	line := scanner.Text()
-	parts := strings.Split(line, " ")
-	if len(parts) != 2 && parts[0] != "Monkey" {
-		return nil, errorDone
+func NewMonkey(b *straid.Block) (*Monkey, error) {
+	if err := b.Expect(6); err != nil {
+		return nil, err
 	}
 
-	parts = strings.Split(parts[1], ":")
-	num, err := strconv.ParseInt(parts[0], 10, 64)
-	if err != nil {
+	var num int
+	if err := b.Scan(0, "Monkey {}:", &num); err != nil {
 		return nil, err
 	}
 
-	scanner.Scan()
-	line = scanner.Text()
-	parts = strings.Split(line, ":")
-	parts[1] = strings.TrimSpace(parts[1])
-	parts = strings.Split(parts[1], ",")
-
-	items := make([]int64, 0)
-	for _, is := range parts {
-		is = strings.TrimSpace(is)
-		i, err := strconv.ParseInt(is, 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		items = append(items, i)
+	var items []int64
+	if err := b.Scan(1, "Starting items: {}", &items); err != nil {
+		return nil, err
 	}
 
 	// Operation
-	scanner.Scan()
-	line = scanner.Text()
-	parts = strings.Split(line, "new = ")
-	line = parts[1]
+	var op, operand string
+	if err := b.Scan(2, "Operation: new = old {} {}", &op, &operand); err != nil {
+		return nil, err
+	}
 
 	var wf WorryFunc
-	if line == "old * old" {
+	if op == "*" && operand == "old" {
 		wf = SquareFunc()
 	} else {
-		parts = strings.Split(line, " ")
-		operand, err := strconv.ParseInt(parts[2], 10, 64)
+		val, err := straid.ParseInt(operand)
 		if err != nil {
-			return nil, err
+			return nil, b.Wrap(2, err)
 		}
-		if parts[1] == "+" {
-			wf = AddFunc(operand)
-		} else if parts[1] == "*" {
-			wf = TimesFunc(operand)
+		if op == "+" {
+			wf = AddFunc(val)
+		} else if op == "*" {
+			wf = TimesFunc(val)
 		} else {
-			return nil, fmt.Errorf("bad operand")
+			return nil, b.Errorf(2, "bad operator %q", op)
 		}
 	}
 
-	scanner.Scan()
-	line = scanner.Text()
-	parts = strings.Split(line, " ")
-	test, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
-	if err != nil {
+	var test int64
+	if err := b.Scan(3, "Test: divisible by {}", &test); err != nil {
 		return nil, err
 	}
 
-	scanner.Scan()
-	line = scanner.Text()
-	parts = strings.Split(line, " ")
-	tt, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
-	if err != nil {
+	var tt int
+	if err := b.Scan(4, "If true: throw to monkey {}", &tt); err != nil {
 		return nil, err
 	}
 
-	scanner.Scan()
-	line = scanner.Text()
-	parts = strings.Split(line, " ")
-	ft, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
-	if err != nil {
+	var ft int
+	if err := b.Scan(5, "If false: throw to monkey {}", &ft); err != nil {
 		return nil, err
 	}
 
 	return &Monkey{
-		Number:      int(num),
+		Number:      num,
 		Items:       items,
 		Operation:   wf,
 		TestDivisor: test,
-		TrueTarget:  int(tt),
-		FalseTarget: int(ft),
+		TrueTarget:  tt,
+		FalseTarget: ft,
 		Inspected:   0,
 	}, nil
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
+	reader := straid.NewBlockReader("day11 input", os.Stdin)
 
 	monkeys := make([]*Monkey, 0)
-	// Load all the monkeys
-	var m *Monkey
-	var err error = nil
-	for err == nil {
-		m, err = NewMonkey(scanner)
-		if err == nil {
-			monkeys = append(monkeys, m)
-		} else if errors.Is(err, errorDone) {
-			break
-		} else {
-			panic(err)
+	// Load all the monkeys, one per block.
+	for reader.Scan() {
+		m, err := NewMonkey(reader.Block())
+		if err != nil {
+			log.Fatal(err)
 		}
+		monkeys = append(monkeys, m)
 	}
 	for _, m := range monkeys {
 		log.Printf("%+v", m)
 	}
 
 	// Create a map and find the mod factor
-	// product of all divisor tests.
-	factor := int64(1)
+	// lcm of all divisor tests.
+	divisors := make([]int64, 0, len(monkeys))
 	monkeyMap := make(map[int]*Monkey)
 	for _, m := range monkeys {
 		monkeyMap[m.Number] = m
-		factor *= m.TestDivisor
+		divisors = append(divisors, m.TestDivisor)
+	}
+	for _, m := range monkeys {
+		for _, t := range []int{m.TrueTarget, m.FalseTarget} {
+			if _, ok := monkeyMap[t]; !ok {
+				log.Fatalf("monkey %v throws to unknown monkey %v", m.Number, t)
+			}
+		}
+	}
+	factor, err := mathaid.LCM(divisors...)
+	if err != nil {
+		panic(err)
 	}
 
 	// Run the rounds (part2 values)
+	progress := aoc.NewProgress("rounds")
 	for round := 0; round < 10000; round++ {
 		for _, m := range monkeys {
 			for m.HasItems() {
@@ -208,7 +188,9 @@ func main() {
 				monkeyMap[r.Number].Recv(r.Item)
 			}
 		}
+		progress.Step(round+1, 10000)
 	}
+	progress.Done()
 
 	processed := make([]int64, len(monkeys))
 	for i, m := range monkeys {
@@ -218,7 +200,7 @@ func main() {
 	sort.Slice(processed, func(i, j int) bool { return processed[i] >= processed[j] })
 	fmt.Printf("answer = %v\n", processed[0]*processed[1])
 
-	if err := scanner.Err(); err != nil {
+	if err := reader.Err(); err != nil {
 		log.Println(err)
 	}
 }
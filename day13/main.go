@@ -1,13 +1,15 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
 	"log"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/list"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
 )
 
 // Allows us to compare lists to numbers, etc.
@@ -138,21 +140,23 @@ func main() {
 	pairs := make([]*Pair, 0)
 	packets := make([]Unit, 0)
 
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		left := Parse(scanner.Text())
-		scanner.Scan()
-		right := Parse(scanner.Text())
+	reader := straid.NewBlockReader("day13 input", os.Stdin)
+
+	// Each pair is a block of two packets.
+	for reader.Scan() {
+		b := reader.Block()
+		if err := b.Expect(2); err != nil {
+			log.Fatal(err)
+		}
+		left := Parse(b.Lines[0])
+		right := Parse(b.Lines[1])
 
 		pairs = append(pairs, &Pair{
 			Left:  left,
 			Right: right,
 		})
 
-		packets = append(packets, left)
-		packets = append(packets, right)
-
-		scanner.Scan()
+		packets = append(packets, left, right)
 	}
 
 	// add the key packets
@@ -167,15 +171,10 @@ func main() {
 
 	log.Printf("# pairs %v", len(pairs))
 
-	s := 0
-	for i, p := range pairs {
-		//log.Printf("-------------")
-		a := p.Left.Compare(p.Right)
-		//log.Printf("%v answer %v", i+1, a)
-		if a < 0 {
-			s += (i + 1)
-		}
-	}
+	ordered := list.Filter(list.Enumerate(pairs), func(p list.Indexed[*Pair]) bool {
+		return p.Value.Left.Compare(p.Value.Right) < 0
+	})
+	s := list.Sum(list.Map(ordered, func(p list.Indexed[*Pair]) int { return p.Index + 1 }))
 	log.Printf("part1 1: %v", s)
 
 	// The way the compare function was written made part 2 trivial!
@@ -198,7 +197,7 @@ func main() {
 	}
 	log.Printf("part1 2: %v", part2)
 
-	if err := scanner.Err(); err != nil {
+	if err := reader.Err(); err != nil {
 		log.Println(err)
 	}
 }
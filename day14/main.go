@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
 	"log"
 	"os"
@@ -27,22 +26,30 @@ func (r *Rocks) String() string {
 	return fmt.Sprintf("%+v", r.Points)
 }
 
-func load(l string) *Rocks {
+func load(l string) (*Rocks, error) {
 	points := make([]*twod.Pos, 0)
 
+	// offset of the current point in the line, for error reporting.
+	offset := 0
 	parts := strings.Split(l, " -> ")
 	for _, pt := range parts {
-		ptparts := strings.Split(pt, ",")
-		c := straid.AsInt(ptparts[0])
-		r := straid.AsInt(ptparts[1])
+		c, err := straid.FieldInt(pt, ",", 0)
+		if err != nil {
+			return nil, straid.Offset(err, offset)
+		}
+		r, err := straid.FieldInt(pt, ",", 1)
+		if err != nil {
+			return nil, straid.Offset(err, offset)
+		}
 		points = append(points, &twod.Pos{
 			Row: int(r),
 			Col: int(c),
 		})
+		offset += len(pt) + len(" -> ")
 	}
 	return &Rocks{
 		Points: points,
-	}
+	}, nil
 }
 
 func printRow(row []int, min int) {
@@ -126,15 +133,18 @@ func drawLine(g Grid, r *Rocks) {
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
+	reader := straid.NewLineReader("day14 input", os.Stdin)
+	reader.SkipBlank = true
 
 	lines := make([]*Rocks, 0)
 
 	minC := 10000000
 	var maxR, maxC int
-	for scanner.Scan() {
-		line := scanner.Text()
-		nl := load(line)
+	for reader.Scan() {
+		nl, err := load(reader.Text())
+		if err != nil {
+			log.Fatal(reader.Wrap(err))
+		}
 		lines = append(lines, nl)
 
 		for _, p := range nl.Points {
@@ -200,7 +210,7 @@ func main() {
 	log.Printf("part 1: %v", part1-1)
 	log.Printf("part 2: %v", part2+1)
 
-	if err := scanner.Err(); err != nil {
+	if err := reader.Err(); err != nil {
 		log.Println(err)
 	}
 }
@@ -1,38 +1,35 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"flag"
 	"fmt"
 	"log"
-	"math"
 	"os"
-	"sort"
-	"strings"
+	"sync/atomic"
 
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/collections"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/interval"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/mathaid"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/parallel"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/twod"
 )
 
-func Parse(s string) (*twod.Pos, *twod.Pos) {
-	// Sensor at x=2, y=18: closest beacon is at x=-2, y=15
-	s = strings.ReplaceAll(s, "Sensor at ", "")
-	s = strings.ReplaceAll(s, ": closest beacon is at ", ",")
-	s = strings.ReplaceAll(s, " ", "")
-	s = strings.ReplaceAll(s, "x=", "")
-	s = strings.ReplaceAll(s, "y=", "")
-
-	parts := strings.Split(s, ",")
+var (
+	row  = flag.Int("row", 2000000, "row to count for part 1, the sample uses 10")
+	size = flag.Int("size", 4000000, "the beacon is in 0 to this in both directions, the sample uses 20")
+)
 
-	sensor := &twod.Pos{
-		Row: int(straid.AsInt(parts[1])),
-		Col: int(straid.AsInt(parts[0])),
-	}
-	beacon := &twod.Pos{
-		Row: int(straid.AsInt(parts[3])),
-		Col: int(straid.AsInt(parts[2])),
+func Parse(s string) (*twod.Pos, *twod.Pos, error) {
+	sensor, beacon := &twod.Pos{}, &twod.Pos{}
+	err := straid.Scan("Sensor at x={}, y={}: closest beacon is at x={}, y={}", s,
+		&sensor.Col, &sensor.Row, &beacon.Col, &beacon.Row)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	return sensor, beacon
+	return sensor, beacon, nil
 }
 
 type Pair struct {
@@ -49,123 +46,116 @@ func NewPair(s, b *twod.Pos) *Pair {
 	}
 }
 
-type RangeList []*Range
-
-func (rl RangeList) Sort() {
-	sort.Slice(rl, func(i, j int) bool {
-		if rl[i].Low == rl[j].Low {
-			return rl[i].High <= rl[j].High
-		}
-		return rl[i].Low <= rl[j].Low
-	})
-}
-
-type Range struct {
-	Low  int
-	High int
-}
-
-func (r *Range) Length() int {
-	return r.High - r.Low + 2 // inclusive
-}
-
-func (r *Range) String() string {
-	return fmt.Sprintf("[%v - %v]", r.Low, r.High)
-}
-
-func NewRange(l, h int) *Range {
-	return &Range{Low: l, High: h}
-}
-
-// assumes sorted.
-func (r *Range) Merge(o *Range) error {
-	if o.Low >= r.Low && o.Low <= r.High {
-		if o.High > r.High {
-			r.High = o.High
-		}
-		return nil
-	}
-	return fmt.Errorf("found it")
-}
-
-func rowRanges(row int, pairs []*Pair) RangeList {
-	ranges := make(RangeList, 0, len(pairs))
+// rowCover is the columns in row that are within range of a sensor.
+func rowCover(row int, pairs []*Pair) *interval.IntervalSet[int] {
+	cover := interval.NewSet[int]()
 	for _, pair := range pairs {
 		dist := pair.dist
-		rDist := int(math.Abs(float64(pair.sensor.Row - row)))
+		rDist := mathaid.Abs(pair.sensor.Row - row)
 		if rDist > dist {
 			continue
 		}
 
 		rem := dist - rDist
-		thisRange := NewRange(pair.sensor.Col-rem, pair.sensor.Col+rem)
-		ranges = append(ranges, thisRange)
+		cover.Insert(interval.New(pair.sensor.Col-rem, pair.sensor.Col+rem))
 	}
-	ranges.Sort()
-	return ranges
+	return cover
 }
 
 func part1(row int, pairs []*Pair) int {
-	ranges := rowRanges(row, pairs)
+	cover := rowCover(row, pairs)
 
-	// Attempt to merge ranges.
-	merged := make(RangeList, 1, len(ranges))
-	merged[0] = ranges[0]
-	for i := 1; i < len(ranges); i++ {
-		err := merged[len(merged)-1].Merge(ranges[i])
-		if err != nil {
-			merged = append(merged, ranges[i])
+	// Beacons that are in the row are covered, but there is a beacon there.
+	beacons := collections.NewSet[int]()
+	for _, pair := range pairs {
+		if pair.beacon.Row == row && cover.Has(pair.beacon.Col) {
+			beacons.Add(pair.beacon.Col)
 		}
 	}
+	return cover.Len() - beacons.Len()
+}
 
-	// Count covered in target row.
-	answer := 0
-	for _, r := range merged {
-		answer += r.Length()
+type band struct {
+	low  int
+	high int
+}
+
+// checkBand looks for the gap in rows low through high.
+func checkBand(ctx context.Context, b band, pairs []*Pair) (*twod.Pos, bool, error) {
+	for r := b.low; r <= b.high; r++ {
+		if r%1000 == 0 && ctx.Err() != nil {
+			return nil, false, nil
+		}
+		gaps := rowCover(r, pairs).Complement(interval.New(0, *size))
+		if !gaps.Empty() {
+			return &twod.Pos{
+				Row: r,
+				Col: gaps.Intervals()[0].Low,
+			}, true, nil
+		}
 	}
-	return answer
+	return nil, false, nil
 }
 
-func part2(pairs []*Pair) *twod.Pos {
-	// Check each row in the search space.
-	for r := 0; r <= 4000000; r++ {
-		ranges := rowRanges(r, pairs)
-
-		// Merge the ranges.
-		thisR := ranges[0]
-		for i := 1; i < len(ranges); i++ {
-			// As soon as one doesn't marge - we found the gap.
-			if err := thisR.Merge(ranges[i]); err != nil {
-				return &twod.Pos{
-					Row: r,
-					Col: thisR.High + 1,
-				}
+func part2(ctx context.Context, pairs []*Pair) (*twod.Pos, error) {
+	// Check each row in the search space, split into bands that run in parallel.
+	rows := *size
+	const bandSize = 50000
+	bands := make([]band, 0, rows/bandSize+1)
+	for low := 0; low <= rows; low += bandSize {
+		bands = append(bands, band{low: low, high: mathaid.Min(low+bandSize-1, rows)})
+	}
+
+	var done int32
+	p, found, err := parallel.First(ctx, parallel.Workers(), bands,
+		func(ctx context.Context, b band) (*twod.Pos, bool, error) {
+			p, found, err := checkBand(ctx, b, pairs)
+			if err == nil && !found && ctx.Err() == nil {
+				atomic.AddInt32(&done, 1)
 			}
-		}
+			return p, found, err
+		})
+	if err != nil {
+		return nil, fmt.Errorf("checked %v of %v bands of %v rows: %w", atomic.LoadInt32(&done), len(bands), bandSize, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("no gap found")
 	}
-	return nil
+	return p, nil
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
+	reader := straid.NewLineReader("day15 input", os.Stdin)
+	reader.SkipBlank = true
 
 	pairs := make([]*Pair, 0)
-	for scanner.Scan() {
-		line := scanner.Text()
-		s, b := Parse(line)
+	for reader.Scan() {
+		s, b, err := Parse(reader.Text())
+		if err != nil {
+			log.Fatal(reader.Wrap(err))
+		}
 		p := NewPair(s, b)
 		// log.Printf("%v %v dist: %v", s, b, p.dist)
 		pairs = append(pairs, p)
 	}
 
-	part1 := part1(2000000, pairs)
+	ctx, cancel := aoc.Context()
+	defer cancel()
+
+	part1 := part1(*row, pairs)
 	log.Printf("part 1: %v\n", part1)
 
-	p := part2(pairs)
-	log.Printf("Candidate: %v", p)
-	log.Printf("Part 2: %v", p.Col*4000000+p.Row)
+	aoc.Part(ctx, "Part 2", func(ctx context.Context) (int, error) {
+		p, err := part2(ctx, pairs)
+		if err != nil {
+			return 0, err
+		}
+		log.Printf("Candidate: %v", p)
+		// The tuning frequency is fixed by the puzzle, it doesn't follow -size.
+		return p.Col*4000000 + p.Row, nil
+	})
 
-	if err := scanner.Err(); err != nil {
+	if err := reader.Err(); err != nil {
 		log.Println(err)
 	}
 }
@@ -1,13 +1,14 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
 	"log"
 	"os"
 	"sort"
 	"strings"
 
+	"github.com/mikehelmick/AdventOfCode2022/pkg/bitset"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/memo"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
 )
 
@@ -15,44 +16,61 @@ type Valve struct {
 	name   string
 	rate   int
 	tunnel []string
+	// position in the open valve bitmasks, only set for valves worth opening.
+	bit int
 }
 
-func LoadValve(s string) *Valve {
+// valvePatterns are the two ways a valve is written, depending on how many
+// tunnels it has. The wording picks which one a line is meant to match.
+var valvePatterns = []struct {
+	wording string
+	pattern string
+}{
 	// Valve AA has flow rate=0; tunnels lead to valves DD, II, BB
-	s = strings.ReplaceAll(s, "Valve ", "")
-	s = strings.ReplaceAll(s, " has flow rate=", ",")
-	s = strings.ReplaceAll(s, "; tunnels lead to valves ", ",")
-	s = strings.ReplaceAll(s, "; tunnel leads to valve ", ",")
-	s = strings.ReplaceAll(s, " ", "")
-
-	parts := strings.Split(s, ",")
-	name := parts[0]
-	rate := int(straid.AsInt(parts[1]))
-	tunnel := make([]string, 0)
-	for i := 2; i < len(parts); i++ {
-		tunnel = append(tunnel, parts[i])
-	}
-	return &Valve{
-		name:   name,
-		rate:   rate,
-		tunnel: tunnel,
-	}
+	{"; tunnels lead to valves ", "Valve {} has flow rate={}; tunnels lead to valves {}"},
+	// Valve HH has flow rate=22; tunnel leads to valve GG
+	{"; tunnel leads to valve ", "Valve {} has flow rate={}; tunnel leads to valve {}"},
+}
+
+func LoadValve(s string) (*Valve, error) {
+	var name, tunnels string
+	var rate int
+	for _, p := range valvePatterns {
+		if !strings.Contains(s, p.wording) {
+			continue
+		}
+		if err := straid.Scan(p.pattern, s, &name, &rate, &tunnels); err != nil {
+			return nil, err
+		}
+		return &Valve{
+			name:   name,
+			rate:   rate,
+			tunnel: strings.Split(tunnels, ", "),
+		}, nil
+	}
+	return nil, &straid.ParseError{Text: s, Col: 1,
+		Err: fmt.Errorf("expected %q or %q", valvePatterns[0].pattern, valvePatterns[1].pattern)}
 }
 
 type ValveMap map[string]*Valve
 
+type pathKey struct {
+	from string
+	to   string
+}
+
 // instead of pre-calculating the all pairs shortest path, just calculate on
 // demand and cache the answer. Shared between both parts, so it's not that
 // expensive and was faster to write.
-var spCache = make(map[string]int)
+var spCache = memo.New[pathKey, int](nil)
 
 func shortestPath(pos string, target string, valves ValveMap) int {
-	idx := fmt.Sprintf("%v-%v", pos, target)
-	if v, ok := spCache[idx]; ok {
-		//fmt.Printf("path from %v to %v is %v\n", pos, target, v)
-		return v
-	}
+	return spCache.Do(pathKey{from: pos, to: target}, func() int {
+		return bfs(pos, target, valves)
+	})
+}
 
+func bfs(pos string, target string, valves ValveMap) int {
 	visited := make(map[string]bool)
 	visited[pos] = true
 	wave := []string{pos}
@@ -61,7 +79,6 @@ func shortestPath(pos string, target string, valves ValveMap) int {
 		next := make([]string, 0)
 		for _, p := range wave {
 			if p == target {
-				spCache[idx] = dist
 				return dist
 			}
 			for _, cand := range valves[p].tunnel {
@@ -74,7 +91,7 @@ func shortestPath(pos string, target string, valves ValveMap) int {
 		wave = next
 		dist++
 	}
-	panic(fmt.Sprintf("%+v", spCache))
+	panic(fmt.Sprintf("no path from %v to %v", pos, target))
 }
 
 // Represents a valid solution under the constraints
@@ -83,20 +100,20 @@ type Solution struct {
 	opened []string
 	flow   int
 
-	cache map[string]bool
+	mask bitset.Bits64
 }
 
-func NewSolution(path []string, flow int) *Solution {
+func NewSolution(path []string, flow int, valves ValveMap) *Solution {
 	op := make([]string, len(path))
 	copy(op, path)
-	cache := make(map[string]bool)
+	var mask bitset.Bits64
 	for _, v := range path {
-		cache[v] = true
+		mask = mask.Set(valves[v].bit)
 	}
 	return &Solution{
 		opened: op,
 		flow:   flow,
-		cache:  cache,
+		mask:   mask,
 	}
 }
 
@@ -106,74 +123,57 @@ func (s *Solution) String() string {
 
 // Disjoint returns true if two solutions share no common valves.
 func (s *Solution) Disjoint(o *Solution) bool {
-	for k := range o.cache {
-		if s.cache[k] {
-			return false
-		}
-	}
-	return true
+	return s.mask.Disjoint(o.mask)
 }
 
 // dfs does a depth first search attempting to open valves and returns all possible solutions
 // based on the starting condition.
-func dfs(check map[string]bool, path []string, valves ValveMap, press int, minute int, time int, pos string) []*Solution {
+// check is the valves left to open, names maps the bits back to valves.
+func dfs(check bitset.Bits64, names []string, path []string, valves ValveMap, press int, minute int, time int, pos string) []*Solution {
 	// out of time, we have a solution.
 	if minute > time {
-		return []*Solution{NewSolution(path, press)}
+		return []*Solution{NewSolution(path, press, valves)}
 	}
 
 	// just moved to pos, open it and account for new flow up to time.
 	minute++
 	press += ((time - minute) * valves[pos].rate)
 	// that was the last possible valve to open, so this is a solution.
-	if len(check) == 0 {
-		return []*Solution{NewSolution(path, press)}
-	}
-
-	// make a defensive copy of the map, otherwise things get weird.
-	toOpen := make(map[string]bool)
-	for k, v := range check {
-		toOpen[k] = v
+	if check.Empty() {
+		return []*Solution{NewSolution(path, press, valves)}
 	}
 
 	sols := make([]*Solution, 0)
 	// accumulate all possible solutions when pos is opened and check is left to open.
-	for k, _ := range toOpen {
-		delete(toOpen, k)
+	check.Each(func(i int) {
+		k := names[i]
 		path = append(path, k)
-		newSols := dfs(toOpen, path, valves, press, minute+shortestPath(pos, k, valves), time, k)
-		toOpen[k] = true
+		newSols := dfs(check.Clear(i), names, path, valves, press, minute+shortestPath(pos, k, valves), time, k)
 		path = path[0 : len(path)-1]
 		sols = append(sols, newSols...)
-	}
+	})
 	return sols
 }
 
-func getSolutions(toOpen map[string]bool, valves ValveMap, time int, pos string) []*Solution {
-	check := make(map[string]bool)
-	for k, v := range toOpen {
-		check[k] = v
-	}
-
+func getSolutions(toOpen bitset.Bits64, names []string, valves ValveMap, time int, pos string) []*Solution {
 	sols := make([]*Solution, 0)
-	for k, _ := range toOpen {
+	toOpen.Each(func(i int) {
 		// if we open k first... what's the payoff
-		delete(check, k)
-		newSols := dfs(check, []string{k}, valves, 0, shortestPath(pos, k, valves), time, k)
-		check[k] = true
+		k := names[i]
+		newSols := dfs(toOpen.Clear(i), names, []string{k}, valves, 0, shortestPath(pos, k, valves), time, k)
 		sols = append(sols, newSols...)
-	}
+	})
 	return sols
 }
 
-func part1(toOpen map[string]bool, valves ValveMap, time int, pos string) int {
-	sols := getSolutions(toOpen, valves, time, pos)
+func part1(toOpen bitset.Bits64, names []string, valves ValveMap, time int, pos string) int {
+	sols := getSolutions(toOpen, names, valves, time, pos)
 	sort.Slice(sols, func(i, j int) bool { return sols[i].flow >= sols[j].flow })
 	return sols[0].flow
 }
 
-func part2(toOpen map[string]bool, valves ValveMap, time int, pos string) int {
-	sols := getSolutions(toOpen, valves, time, pos)
+func part2(toOpen bitset.Bits64, names []string, valves ValveMap, time int, pos string) int {
+	sols := getSolutions(toOpen, names, valves, time, pos)
 	sort.Slice(sols, func(i, j int) bool { return sols[i].flow >= sols[j].flow })
 
 	// find the two highest (sorted) non overlapping
@@ -185,7 +185,6 @@ func part2(toOpen map[string]bool, valves ValveMap, time int, pos string) int {
 			if nm <= max {
 				break
 			}
-			// avoid the expensive operation if we don't need it.
 			if sols[i].Disjoint(sols[j]) {
 				max = nm
 			}
@@ -195,26 +194,34 @@ func part2(toOpen map[string]bool, valves ValveMap, time int, pos string) int {
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
+	reader := straid.NewLineReader("day16 input", os.Stdin)
+	reader.SkipBlank = true
 
 	valves := make(ValveMap)
 	// Restrict the consideration to non-zero flow vales only.
-	toOpen := make(map[string]bool, len(valves))
-	for scanner.Scan() {
-		line := scanner.Text()
-		v := LoadValve(line)
+	names := make([]string, 0)
+	for reader.Scan() {
+		v, err := LoadValve(reader.Text())
+		if err != nil {
+			log.Fatal(reader.Wrap(err))
+		}
 		valves[v.name] = v
 		if v.rate > 0 {
-			toOpen[v.name] = true
+			v.bit = len(names)
+			names = append(names, v.name)
 		}
 	}
-	log.Printf("must open: %+v", toOpen)
+	if len(names) > 64 {
+		log.Fatalf("too many valves to open: %v", len(names))
+	}
+	toOpen := bitset.Full(len(names))
+	log.Printf("must open: %+v", names)
 
-	pressure := part1(toOpen, valves, 30, "AA")
+	pressure := part1(toOpen, names, valves, 30, "AA")
 	log.Printf("part 1 : %v", pressure)
-	log.Printf("part 2 : %v", part2(toOpen, valves, 26, "AA"))
+	log.Printf("part 2 : %v", part2(toOpen, names, valves, 26, "AA"))
 
-	if err := scanner.Err(); err != nil {
+	if err := reader.Err(); err != nil {
 		log.Println(err)
 	}
 }
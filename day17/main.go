@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/twod"
 )
 
@@ -216,6 +217,7 @@ func main() {
 	}
 
 	jetI := 0
+	progress := aoc.NewProgress("rocks")
 	for i := 0; i < 2022; i++ {
 		g := glphs[i%len(glphs)]
 		p := twod.NewPos(0, 2)
@@ -260,8 +262,10 @@ func main() {
 			//print(chamber)
 		}
 		//print(chamber)
+		progress.Step(i+1, 2022, aoc.M("jet", jetI))
 	}
 
+	progress.Done()
 	//print(chamber)
 
 	part1 := int64(RockHeight(chamber))
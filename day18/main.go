@@ -1,12 +1,12 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
 	"log"
 	"os"
 	"strings"
 
+	"github.com/mikehelmick/AdventOfCode2022/pkg/dsu"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/mathaid"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/threed"
@@ -143,22 +143,83 @@ func cubeBFS(bounds []int, m map[string]bool) int {
 	return count
 }
 
-func Load(s string) *Cube {
-	parts := strings.Split(s, ",")
-	return NewCube(
-		int(straid.AsInt(parts[0])),
-		int(straid.AsInt(parts[1])),
-		int(straid.AsInt(parts[2])),
-	)
+// airPockets groups all the air in bounds into connected components, anything
+// not connected to the corner (outside air) is trapped inside the droplet.
+func airPockets(bounds []int, m map[string]bool) [][]threed.Pos {
+	air := dsu.New[threed.Pos]()
+	for x := bounds[0]; x <= bounds[1]; x++ {
+		for y := bounds[2]; y <= bounds[3]; y++ {
+			for z := bounds[4]; z <= bounds[5]; z++ {
+				p := threed.NewPos(x, y, z)
+				if m[p.String()] {
+					continue
+				}
+				air.Add(*p)
+				// only need to look backwards, forward neighbors join later.
+				for _, n := range []*threed.Pos{threed.NewPos(x-1, y, z), threed.NewPos(x, y-1, z), threed.NewPos(x, y, z-1)} {
+					if air.Contains(*n) {
+						air.Union(*p, *n)
+					}
+				}
+			}
+		}
+	}
+
+	// The bounds leave a gap around the droplet, so the corner is always air.
+	outside, _ := air.Find(*threed.NewPos(bounds[0], bounds[2], bounds[4]))
+	pockets := make([][]threed.Pos, 0)
+	for _, c := range air.Components() {
+		if !air.Connected(c[0], outside) {
+			pockets = append(pockets, c)
+		}
+	}
+	return pockets
+}
+
+// exteriorSides is the part 1 answer minus every face that touches trapped air.
+func exteriorSides(sides int, bounds []int, m map[string]bool) int {
+	pockets := airPockets(bounds, m)
+	log.Printf("found %v trapped air pockets", len(pockets))
+	for _, pocket := range pockets {
+		for _, p := range pocket {
+			for _, d := range threed.Adj {
+				n := p.Clone()
+				n.Add(d)
+				if m[n.String()] {
+					sides--
+				}
+			}
+		}
+	}
+	return sides
+}
+
+func Load(s string) (*Cube, error) {
+	if n := len(strings.Split(s, ",")); n != 3 {
+		return nil, &straid.ParseError{Text: s, Col: 1, Err: fmt.Errorf("expected x,y,z found %d fields", n)}
+	}
+	coords := make([]int, 3)
+	for i := range coords {
+		v, err := straid.FieldInt(s, ",", i)
+		if err != nil {
+			return nil, err
+		}
+		coords[i] = int(v)
+	}
+	return NewCube(coords[0], coords[1], coords[2]), nil
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
+	reader := straid.NewLineReader("day18 input", os.Stdin)
+	reader.SkipBlank = true
 
 	cubes := make([]*Cube, 0)
-	for scanner.Scan() {
-		line := scanner.Text()
-		cubes = append(cubes, Load(line))
+	for reader.Scan() {
+		c, err := Load(reader.Text())
+		if err != nil {
+			log.Fatal(reader.Wrap(err))
+		}
+		cubes = append(cubes, c)
 	}
 
 	for i := 0; i < len(cubes); i++ {
@@ -186,8 +247,9 @@ func main() {
 
 	part2 := cubeBFS(bounds, cubeMap)
 	log.Printf("part 2: %v", part2)
+	log.Printf("part 2 (air pockets): %v", exteriorSides(sides, bounds, cubeMap))
 
-	if err := scanner.Err(); err != nil {
+	if err := reader.Err(); err != nil {
 		log.Println(err)
 	}
 }
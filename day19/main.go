@@ -1,13 +1,16 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"sort"
-	"strings"
+	"sync/atomic"
 
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/mathaid"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/parallel"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
 )
 
@@ -56,31 +59,25 @@ func NewBlueprint() *Blueprint {
 }
 
 // Load turns a single line from the input into a Blueprint struct.
-func Load(s string) *Blueprint {
+func Load(s string) (*Blueprint, error) {
 	bp := NewBlueprint()
-	// Blueprint 1: Each ore robot costs 3 ore.    // 0,1
-	// Each clay robot costs 4 ore.                // 2
-	// Each obsidian robot costs 2 ore and 20 clay.// 3, 4
-	// Each geode robot costs 4 ore and 7 obsidian.// 5, 6
-	s = strings.ReplaceAll(s, "Blueprint ", "")
-	s = strings.Replace(s, ": Each ore robot costs ", ",", 1)
-	s = strings.Replace(s, " ore. Each clay robot costs ", ",", 1)
-	s = strings.Replace(s, " ore. Each obsidian robot costs ", ",", 1)
-	s = strings.Replace(s, " ore and ", ",", 1)
-	s = strings.Replace(s, " clay. Each geode robot costs ", ",", 1)
-	s = strings.Replace(s, " ore and ", ",", 1)
-	s = strings.Replace(s, " obsidian.", "", 1)
-
-	p := strings.Split(s, ",")
-	bp.Number = int(straid.AsInt(p[0]))
-	bp.Costs[ORE][ORE] = int(straid.AsInt(p[1]))
-	bp.Costs[CLAY][ORE] = int(straid.AsInt(p[2]))
-	bp.Costs[OBSIDIAN][ORE] = int(straid.AsInt(p[3]))
-	bp.Costs[OBSIDIAN][CLAY] = int(straid.AsInt(p[4]))
-	bp.Costs[GEODE][ORE] = int(straid.AsInt(p[5]))
-	bp.Costs[GEODE][OBSIDIAN] = int(straid.AsInt(p[6]))
+	var oreOre, clayOre, obsOre, obsClay, geodeOre, geodeObs int
+	err := straid.Scan("Blueprint {}: Each ore robot costs {} ore. "+
+		"Each clay robot costs {} ore. "+
+		"Each obsidian robot costs {} ore and {} clay. "+
+		"Each geode robot costs {} ore and {} obsidian.", s,
+		&bp.Number, &oreOre, &clayOre, &obsOre, &obsClay, &geodeOre, &geodeObs)
+	if err != nil {
+		return nil, err
+	}
 
-	return bp
+	bp.Costs[ORE][ORE] = oreOre
+	bp.Costs[CLAY][ORE] = clayOre
+	bp.Costs[OBSIDIAN][ORE] = obsOre
+	bp.Costs[OBSIDIAN][CLAY] = obsClay
+	bp.Costs[GEODE][ORE] = geodeOre
+	bp.Costs[GEODE][OBSIDIAN] = geodeObs
+	return bp, nil
 }
 
 // State represents a point in time search state.
@@ -211,11 +208,15 @@ func (bp *Blueprint) Enumerate(s *State) []*State {
 	return next
 }
 
-func search(bp *Blueprint, minutes int) int {
+func search(ctx context.Context, bp *Blueprint, minutes int) (int, error) {
 	states := []*State{NewState()}
+	progress := aoc.ProgressFrom(ctx)
 
 	for i := 1; i <= minutes; i++ {
-		log.Printf("Blueprint %v Minute %v States: %v", bp.Number, i, len(states))
+		if err := ctx.Err(); err != nil {
+			return 0, fmt.Errorf("blueprint %v stopped at minute %v of %v with %v states: %w", bp.Number, i, minutes, len(states), err)
+		}
+		progress.Step(i, minutes, aoc.M("blueprint", bp.Number), aoc.M("states", len(states)))
 		nextStates := make(map[string]*State)
 		for _, state := range states {
 			state.Tick()
@@ -254,39 +255,71 @@ func search(bp *Blueprint, minutes int) int {
 	sort.Slice(states, func(i, j int) bool {
 		return states[i].Geode >= states[j].Geode
 	})
-	return states[0].Geode
+	return states[0].Geode, nil
+}
+
+// geodes searches all the blueprints at the same time, they are independent.
+func geodes(ctx context.Context, data []*Blueprint, minutes int) ([]int, error) {
+	var done int32
+	results, err := parallel.Map(ctx, parallel.Workers(), data, func(ctx context.Context, bp *Blueprint) (int, error) {
+		a, err := search(ctx, bp, minutes)
+		if err != nil {
+			return 0, err
+		}
+		atomic.AddInt32(&done, 1)
+		log.Printf("Blueprint %v has %v geods", bp.Number, a)
+		return a, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%v of %v blueprints done, %w", atomic.LoadInt32(&done), len(data), err)
+	}
+	return results, nil
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
+	reader := straid.NewLineReader("day19 input", os.Stdin)
+	reader.SkipBlank = true
 
 	data := make([]*Blueprint, 0)
-	for scanner.Scan() {
-		line := scanner.Text()
-		data = append(data, Load(line))
+	for reader.Scan() {
+		bp, err := Load(reader.Text())
+		if err != nil {
+			log.Fatal(reader.Wrap(err))
+		}
+		data = append(data, bp)
 	}
 	log.Printf("%+v", data)
 
-	total := 0
-	for _, bp := range data {
-		a := search(bp, 24)
-		log.Printf("Blueprint %v has %v geods", bp.Number, a)
-		total += (bp.Number * a)
-	}
-	log.Printf("part 1: %v", total)
+	ctx, cancel := aoc.Context()
+	defer cancel()
+
+	aoc.Part(ctx, "part 1", func(ctx context.Context) (int, error) {
+		results, err := geodes(ctx, data, 24)
+		if err != nil {
+			return 0, err
+		}
+		total := 0
+		for i, bp := range data {
+			total += (bp.Number * results[i])
+		}
+		return total, nil
+	})
 
-	total = 1
-	data = data[0:3]
 	// not super efficient - since we don't cache the states from part 1
 	// but it gets the job done.
-	for _, bp := range data {
-		a := search(bp, 32)
-		log.Printf("Blueprint %v has %v geods", bp.Number, a)
-		total *= a
-	}
-	log.Printf("part 1: %v", total)
+	aoc.Part(ctx, "part 2", func(ctx context.Context) (int, error) {
+		results, err := geodes(ctx, data[0:mathaid.Min(3, len(data))], 32)
+		if err != nil {
+			return 0, err
+		}
+		total := 1
+		for _, a := range results {
+			total *= a
+		}
+		return total, nil
+	})
 
-	if err := scanner.Err(); err != nil {
+	if err := reader.Err(); err != nil {
 		log.Println(err)
 	}
 }
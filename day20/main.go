@@ -1,87 +1,93 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	_ "embed"
 	"fmt"
-	"log"
-	"os"
+	"strings"
 
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/list"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
 )
 
-func solve(data list.List[int64], locator list.List[int], multiplier int64, rounds int) int64 {
-	if multiplier > 1 {
-		for i, d := range data {
-			data[i] = d * multiplier
+// https://adventofcode.com/2022/day/20
+
+//go:embed sample.txt
+var sample string
+
+func mix(ctx context.Context, data []int64, multiplier int64, rounds int) (int64, error) {
+	seq := list.NewSequence[int64]()
+	// handles in the original order, this is the order things get mixed.
+	handles := make([]*list.Element[int64], len(data))
+	var zero *list.Element[int64]
+	for i, d := range data {
+		handles[i] = seq.Append(d * multiplier)
+		if d == 0 {
+			zero = handles[i]
 		}
 	}
+	if zero == nil {
+		return 0, fmt.Errorf("no 0 in the file")
+	}
 
 	l := len(data)
+	progress := aoc.ProgressFrom(ctx)
 	for c := 0; c < rounds; c++ {
-		//fmt.Printf("\n----------\nIteration %v\n", c)
-		for i := 0; i < l; i++ {
-			fromIdx := locator.Find(i)
-
-			value := data[fromIdx]
-			var check int64
-			check, data = data.RemoveAt(fromIdx)
-			if check != value {
-				panic("mismatch")
+		for i, h := range handles {
+			if err := ctx.Err(); err != nil {
+				return 0, fmt.Errorf("stopped in round %v of %v, %v of %v numbers mixed: %w", c+1, rounds, i, l, err)
 			}
-			var locV int
-			locV, locator = locator.RemoveAt(fromIdx)
+			fromIdx := seq.Remove(h)
 
-			dest := int((int64(fromIdx) + value) % int64(l-1))
+			dest := int((int64(fromIdx) + h.Value) % int64(l-1))
 			for dest < 0 {
 				dest += (l - 1)
 			}
-			newPos := int(dest)
-			//fmt.Printf("Moving %v from idx: %v to idx: %v\n", value, fromIdx, newPos)
-			data = data.Add(newPos, value)
-			locator = locator.Add(newPos, locV)
-
-			//fmt.Printf("D: %+v\n", data)
-			//fmt.Printf("L: %+v\n\n", locator)
+			seq.InsertElementAt(dest, h)
+			progress.Step(c*l+i+1, rounds*l, aoc.M("round", c+1))
 		}
-		//fmt.Printf("D: %+v\n", data)
 	}
 
-	zeroIdx := data.Find(0)
-	log.Printf("%v %v %v %v", zeroIdx, (zeroIdx+1000)%l, (zeroIdx+2000)%l, (zeroIdx+3000)%l)
-	sum := data[(zeroIdx+1000)%l] + data[(zeroIdx+2000)%l] + data[(zeroIdx+3000)%l]
-	return sum
+	zeroIdx := seq.IndexOf(zero)
+	sum := seq.At((zeroIdx+1000)%l).Value + seq.At((zeroIdx+2000)%l).Value + seq.At((zeroIdx+3000)%l).Value
+	return sum, nil
 }
 
-func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-
-	data := list.New[int64](0)
-	for scanner.Scan() {
-		line := scanner.Text()
-		data = append(data, straid.AsInt(line))
+// parse reads the encrypted file, one number per line.
+func parse(input string) ([]int64, error) {
+	reader := straid.NewLineReader("day20 input", strings.NewReader(input))
+	reader.SkipBlank = true
 
+	data := make([]int64, 0)
+	for reader.Scan() {
+		v, err := straid.ParseInt(reader.Text())
+		if err != nil {
+			return nil, reader.Wrap(err)
+		}
+		data = append(data, v)
 	}
-	l := len(data)
-	locator := list.New[int](l)
-	for i := 0; i < l; i++ {
-		locator = append(locator, i)
-	}
-	//fmt.Printf("D: %+v\n", data)
-	//fmt.Printf("L: %+v\n", locator)
-	fmt.Printf("Length %v\n\n", len(data))
+	return data, reader.Err()
+}
 
-	p1d := make([]int64, len(data))
-	copy(p1d, data)
-	p1l := make([]int, len(locator))
-	copy(p1l, locator)
-	part1 := solve(p1d, p1l, 1, 1)
-	log.Printf("Part 1: %v", part1)
+type solver struct{}
 
-	part2 := solve(data, locator, 811589153, 10)
-	log.Printf("Part 2: %v", part2)
+func (solver) Part1(ctx context.Context, input string) (any, error) {
+	data, err := parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return mix(ctx, data, 1, 1)
+}
 
-	if err := scanner.Err(); err != nil {
-		log.Println(err)
+func (solver) Part2(ctx context.Context, input string) (any, error) {
+	data, err := parse(input)
+	if err != nil {
+		return nil, err
 	}
+	return mix(ctx, data, 811589153, 10)
+}
+
+func main() {
+	aoc.Main(solver{})
 }
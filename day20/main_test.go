@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSample(t *testing.T) {
+	cases := []struct {
+		name string
+		part func(context.Context, string) (any, error)
+		want any
+	}{
+		{"part 1", solver{}.Part1, int64(3)},
+		{"part 2", solver{}.Part2, int64(1623178306)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.want == nil {
+				t.Skip("TODO: no expected answer yet")
+			}
+			got, err := tc.part(context.Background(), sample)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
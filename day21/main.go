@@ -1,12 +1,12 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
 	"log"
 	"os"
 	"strings"
 
+	"github.com/mikehelmick/AdventOfCode2022/pkg/memo"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/search"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
 )
@@ -15,9 +15,10 @@ type Element struct {
 	Name  string
 	Value int64
 
-	Opp   string
-	Left  *Element
-	Right *Element
+	Opp    string
+	Left   *Element
+	Right  *Element
+	Parent *Element
 
 	LeftName  string
 	RightName string
@@ -28,50 +29,80 @@ func (e *Element) Check() (string, int64) {
 	return fmt.Sprintf("%v == %v (%v)", e.Left.Calculate(), e.Right.Calculate(), d), d
 }
 
-func (e *Element) Calculate() int64 {
+// Results are cached, changing a value only invalidates the path up to the root.
+var results = memo.NewRecursive(func(self func(*Element) int64, e *Element) int64 {
 	switch e.Opp {
 	case "":
 		return e.Value
 	case "+":
-		return e.Left.Calculate() + e.Right.Calculate()
+		return self(e.Left) + self(e.Right)
 	case "-":
-		return e.Left.Calculate() - e.Right.Calculate()
+		return self(e.Left) - self(e.Right)
 	case "*":
-		return e.Left.Calculate() * e.Right.Calculate()
+		return self(e.Left) * self(e.Right)
 	case "/":
-		return e.Left.Calculate() / e.Right.Calculate()
+		return self(e.Left) / self(e.Right)
 	}
 	panic("no op")
+})
+
+func (e *Element) Calculate() int64 {
+	return results.Get(e)
 }
 
-func Load(s string) *Element {
-	parts := strings.Split(s, ": ")
+func (e *Element) SetValue(v int64) {
+	e.Value = v
+	for p := e; p != nil; p = p.Parent {
+		results.Invalidate(p)
+	}
+}
 
-	name := parts[0]
-	if strings.Contains(parts[1], " ") {
-		parts := strings.Split(parts[1], " ")
+func Load(s string) (*Element, error) {
+	name, err := straid.FieldE(s, ": ", 0)
+	if err != nil {
+		return nil, err
+	}
+	job, err := straid.FieldE(s, ": ", 1)
+	if err != nil {
+		return nil, err
+	}
+	// the job starts after "name: "
+	offset := len(name) + len(": ")
+
+	if strings.Contains(job, " ") {
+		parts := strings.Split(job, " ")
+		if len(parts) != 3 {
+			return nil, straid.Offset(&straid.ParseError{Text: job, Err: fmt.Errorf("expected 'a op b'")}, offset+1)
+		}
 		return &Element{
 			Name:      name,
 			Opp:       parts[1],
 			LeftName:  parts[0],
 			RightName: parts[2],
-		}
+		}, nil
+	}
+	v, err := straid.ParseInt(job)
+	if err != nil {
+		return nil, straid.Offset(err, offset)
 	}
 	return &Element{
 		Name:  name,
-		Value: straid.AsInt(parts[1]),
+		Value: v,
 		Opp:   "",
-	}
+	}, nil
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
+	reader := straid.NewLineReader("day21 input", os.Stdin)
+	reader.SkipBlank = true
 
 	elem := make([]*Element, 0)
 	eMap := make(map[string]*Element)
-	for scanner.Scan() {
-		line := scanner.Text()
-		e := Load(line)
+	for reader.Scan() {
+		e, err := Load(reader.Text())
+		if err != nil {
+			log.Fatal(reader.Wrap(err))
+		}
 		elem = append(elem, e)
 		eMap[e.Name] = e
 	}
@@ -80,6 +111,11 @@ func main() {
 		if v.LeftName != "" {
 			v.Left = eMap[v.LeftName]
 			v.Right = eMap[v.RightName]
+			if v.Left == nil || v.Right == nil {
+				log.Fatalf("%v refers to an unknown monkey: %v %v", v.Name, v.LeftName, v.RightName)
+			}
+			v.Left.Parent = v
+			v.Right.Parent = v
 		}
 	}
 
@@ -88,7 +124,7 @@ func main() {
 
 	// This is the binary search check function.
 	test := func(median int64) int64 {
-		eMap["humn"].Value = median
+		eMap["humn"].SetValue(median)
 		res, d := eMap["root"].Check()
 		if d == 0 {
 			log.Printf("check %v", res)
@@ -102,7 +138,7 @@ func main() {
 	part2 := search.BinarySearch(low, high, test)
 	log.Printf("part 2: %v", part2)
 
-	if err := scanner.Err(); err != nil {
+	if err := reader.Err(); err != nil {
 		log.Println(err)
 	}
 }
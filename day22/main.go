@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
 	"log"
 	"os"
@@ -133,20 +132,25 @@ func (m Maze) IsOutOfBounds(p *twod.Pos) bool {
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
+	reader := straid.NewBlockReader("day22 input", os.Stdin)
+
+	// The map, then the path.
+	if !reader.Scan() {
+		log.Fatalf("day22 input: missing map")
+	}
+	lines := reader.Block().Lines
+	if !reader.Scan() {
+		log.Fatalf("day22 input: missing path after the map")
+	}
+	if err := reader.Block().Expect(1); err != nil {
+		log.Fatal(err)
+	}
+	path := reader.Block().Lines[0]
 
-	lines := make([]string, 0)
 	width := 0
-	path := ""
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.Contains(line, "L") {
-			path = line
-		} else {
-			lines = append(lines, line)
-			if len(line) > width {
-				width = len(line)
-			}
+	for _, line := range lines {
+		if len(line) > width {
+			width = len(line)
 		}
 	}
 	width += 2
@@ -173,7 +177,7 @@ func main() {
 	log.Printf("part 2: %v", part2)
 	//fmt.Printf("%+v", maze)
 
-	if err := scanner.Err(); err != nil {
+	if err := reader.Err(); err != nil {
 		log.Println(err)
 	}
 }
@@ -2,10 +2,13 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"log"
 	"os"
 
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/collections"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/mathaid"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/twod"
 )
@@ -71,6 +74,16 @@ func (e *Elf) Move(newp *twod.Pos) {
 	e.Pos = newp
 }
 
+// Clone copies the grid and the elves in it, so it can be simulated without
+// changing g.
+func (g Grid) Clone() Grid {
+	c := make(Grid, len(g))
+	for k, e := range g {
+		c[k] = NewElf(e.Pos.Clone())
+	}
+	return c
+}
+
 func (g Grid) AllEmpty(p *twod.Pos, check []*twod.Pos) bool {
 	for _, c := range check {
 		cand := p.Clone()
@@ -120,27 +133,21 @@ func (g Grid) Print(tl, br *twod.Pos) {
 	}
 }
 
-func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-
-	grid := make(Grid)
-	row := 0
-	for scanner.Scan() {
-		line := scanner.Text()
-		AddRow(grid, row, line)
-		row++
-	}
-
-	topLeft := twod.NewPos(100, 100)
-	botRight := twod.NewPos(0, 0)
-	grid.UpdateBounds(topLeft, botRight)
-	grid.Print(topLeft, botRight)
-
+// simulate moves the elves in grid for the given number of rounds, or until
+// no elf needs to move if rounds is 0, and returns how many rounds it ran.
+func simulate(ctx context.Context, grid Grid, rounds int) (int, error) {
 	order := []int{0, 1, 2, 3}
-	// 1000 was enough to solve part 2 for my input.
-	for i := 0; i < 1000; i++ {
+	moving := len(grid)
+	progress := aoc.ProgressFrom(ctx)
+	for i := 0; rounds == 0 || i < rounds; i++ {
+		progress.Step(i, 0, aoc.M("moving", moving))
+		if err := ctx.Err(); err != nil {
+			return 0, fmt.Errorf("stopped after %v rounds with %v of %v elves still moving: %w", i, moving, len(grid), err)
+		}
 		//log.Printf("Starting round %v, order: %+v", i+1, order)
-		moves := make(map[string][]*twod.Pos)
+		// proposed target for each elf, and how many elves want each target
+		proposals := make(map[string]*twod.Pos)
+		wanted := collections.NewCounter[string]()
 		stable := 0
 		// queue up candidate moves
 		for spt := range grid {
@@ -159,31 +166,29 @@ func main() {
 				}
 			}
 			if !p.Equals(cand) {
-				if _, ok := moves[cand.String()]; !ok {
-					moves[cand.String()] = make([]*twod.Pos, 0)
-				}
-				moves[cand.String()] = append(moves[cand.String()], p)
+				proposals[spt] = cand
+				wanted.Add(cand.String())
 			}
 		}
 
 		if stable == len(grid) {
-			log.Printf("Part 2 : %v", i+1)
-			break
+			return i + 1, nil
 		}
+		moving = len(grid) - stable
 
 		// simple assert that we don't lose anyone.
 		before := len(grid)
-		//fmt.Printf("round %v movers: %+v\n\n", i, moves)
-		for k, movers := range moves {
-			if len(movers) == 1 {
+		//fmt.Printf("round %v proposals: %+v\n\n", i, proposals)
+		for from, newP := range proposals {
+			k := newP.String()
+			if wanted.Get(k) == 1 {
 				if _, ok := grid[k]; ok {
 					panic("invariant violated")
 				}
 				// Move the elf
-				newP := twod.FromString(k)
-				grid[k] = grid[movers[0].String()]
+				grid[k] = grid[from]
 				grid[k].Move(newP)
-				delete(grid, movers[0].String())
+				delete(grid, from)
 			} // else, just don't move them
 		}
 		after := len(grid)
@@ -191,23 +196,46 @@ func main() {
 			panic("elf lost")
 		}
 
-		// Need to reset these as the bounds may shrink during a round.
-		topLeft = twod.NewPos(100, 100)
-		botRight = twod.NewPos(0, 0)
-		grid.UpdateBounds(topLeft, botRight)
-		//fmt.Printf("\n After %v\n", i+1)
-		//grid.Print(topLeft, botRight)
-
 		// Rotate the direction check order for the next round
 		end := order[0]
 		order = append(order[1:], end)
+	}
+	return rounds, nil
+}
 
-		if i == 9 {
-			log.Printf("Bounds: %v %v", topLeft, botRight)
-			log.Printf("part 1: %v", grid.CountEmpty(topLeft, botRight))
-		}
+func main() {
+	scanner := bufio.NewScanner(os.Stdin)
+
+	grid := make(Grid)
+	row := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		AddRow(grid, row, line)
+		row++
 	}
 
+	topLeft := twod.NewPos(100, 100)
+	botRight := twod.NewPos(0, 0)
+	grid.UpdateBounds(topLeft, botRight)
+	grid.Print(topLeft, botRight)
+
+	ctx, cancel := aoc.Context()
+	defer cancel()
+	aoc.Part(ctx, "Part 1", func(ctx context.Context) (int, error) {
+		g := grid.Clone()
+		if _, err := simulate(ctx, g, 10); err != nil {
+			return 0, err
+		}
+		// The bounds may have shrunk as well as grown.
+		topLeft := twod.NewPos(100, 100)
+		botRight := twod.NewPos(0, 0)
+		g.UpdateBounds(topLeft, botRight)
+		return g.CountEmpty(topLeft, botRight), nil
+	})
+	aoc.Part(ctx, "Part 2", func(ctx context.Context) (int, error) {
+		return simulate(ctx, grid, 0)
+	})
+
 	if err := scanner.Err(); err != nil {
 		log.Println(err)
 	}
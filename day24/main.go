@@ -2,10 +2,13 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"log"
 	"os"
 
+	"github.com/mikehelmick/AdventOfCode2022/pkg/aoc"
+	"github.com/mikehelmick/AdventOfCode2022/pkg/mathaid"
 	"github.com/mikehelmick/AdventOfCode2022/pkg/twod"
 )
 
@@ -209,21 +212,44 @@ func main() {
 	couldBe[*start] = true
 	target := grid.FindTarget(len(grid) - 1)
 	log.Printf("Start %v Target %v", start, target)
+	// Blizzards only move in the interior, the wind repeats with this period.
+	period, err := mathaid.LCM(max.Row-2, max.Col-2)
+	if err != nil {
+		panic(err)
+	}
+	log.Printf("Blizzard period %v", period)
 
 	grid.Print(blizzards, couldBe)
-	grid, blizzards, firstPass := search(grid, blizzards, couldBe, start, target, max)
-	log.Printf("Part 1: %v", firstPass)
 
-	// Go back to start
-	couldBe = make(map[twod.Pos]bool)
-	couldBe[*target] = true
-	grid, blizzards, secondPass := search(grid, blizzards, couldBe, target, start, max)
+	ctx, cancel := aoc.Context()
+	defer cancel()
+	firstPass, ok := aoc.Part(ctx, "Part 1", func(ctx context.Context) (int, error) {
+		var minutes int
+		var err error
+		grid, blizzards, minutes, err = search(ctx, grid, blizzards, couldBe, start, target, max, period)
+		return minutes, err
+	})
+	if !ok {
+		return
+	}
 
-	couldBe = make(map[twod.Pos]bool)
-	couldBe[*start] = true
-	_, _, thirdPass := search(grid, blizzards, couldBe, start, target, max)
+	aoc.Part(ctx, "Part 2", func(ctx context.Context) (int, error) {
+		// Go back to start
+		couldBe := make(map[twod.Pos]bool)
+		couldBe[*target] = true
+		grid, blizzards, secondPass, err := search(ctx, grid, blizzards, couldBe, target, start, max, period)
+		if err != nil {
+			return 0, fmt.Errorf("going back for the snacks: %w", err)
+		}
 
-	log.Printf("Part 2: %v", firstPass+secondPass+thirdPass)
+		couldBe = make(map[twod.Pos]bool)
+		couldBe[*start] = true
+		_, _, thirdPass, err := search(ctx, grid, blizzards, couldBe, start, target, max, period)
+		if err != nil {
+			return 0, fmt.Errorf("going back to the goal: %w", err)
+		}
+		return firstPass + secondPass + thirdPass, nil
+	})
 
 	if err := scanner.Err(); err != nil {
 		log.Println(err)
@@ -232,14 +258,28 @@ func main() {
 
 // Does a BFS from couldBe (set) to target
 // This version uses quantum elves, that can be in all valid positions at the same time :)
-func search(grid Grid, blizzards BlizzardMap, couldBe map[twod.Pos]bool, start, target, max *twod.Pos) (Grid, BlizzardMap, int) {
+// period is how often the blizzards repeat, used to give up if target can't be reached.
+func search(ctx context.Context, grid Grid, blizzards BlizzardMap, couldBe map[twod.Pos]bool, start, target, max *twod.Pos, period int) (Grid, BlizzardMap, int, error) {
 	minute := 0
+	progress := aoc.ProgressFrom(ctx)
+	// Waiting out a whole period at the start and then making the same moves is
+	// always possible, so the positions at minute m+period include the ones at
+	// m. If there are no more, there never will be and target can't be reached.
+	seen := make([]int, period)
 	for !couldBe[*target] {
 		if len(couldBe) == 0 {
-			panic("we lost all the elves...")
+			return nil, nil, 0, fmt.Errorf("we lost all the elves at minute %v", minute)
+		}
+		phase := minute % period
+		if minute >= period && len(couldBe) == seen[phase] {
+			return nil, nil, 0, fmt.Errorf("no way through, the same %v positions come back every %v minutes", len(couldBe), period)
+		}
+		seen[phase] = len(couldBe)
+		if err := ctx.Err(); err != nil {
+			return nil, nil, 0, fmt.Errorf("stopped at minute %v with %v possible positions: %w", minute, len(couldBe), err)
 		}
 		minute++
-		log.Printf("Minute %v", minute)
+		progress.Step(minute, 0, aoc.M("positions", len(couldBe)))
 
 		grid, blizzards = grid.BlowWind(blizzards)
 
@@ -263,7 +303,6 @@ func search(grid Grid, blizzards BlizzardMap, couldBe map[twod.Pos]bool, start,
 			}
 		}
 		couldBe = nextElf
-		grid.Print(blizzards, couldBe)
 	}
-	return grid, blizzards, minute
+	return grid, blizzards, minute, nil
 }
@@ -0,0 +1,88 @@
+// Package aoc has the shared plumbing for running a day: command line flags,
+// cancellation and reporting each part's answer.
+package aoc
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+)
+
+var timeout = flag.Duration("timeout", 0, "give up on each part after this long, 0 for no limit")
+
+// Parse parses the command line, including the flags every day takes like
+// -timeout, so days with their own flags should call it instead of flag.Parse.
+// It's safe to call more than once.
+func Parse() {
+	if err := parse(flag.CommandLine, os.Args[1:]); err != nil {
+		log.Printf("pprof: %v", err)
+	}
+}
+
+// parse does the work of Parse on fs, starting the pprof server if fs has a
+// -pprof-http address. The error is from the server, fs reports bad flags.
+func parse(fs *flag.FlagSet, args []string) error {
+	if fs.Parsed() {
+		return nil
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if f := fs.Lookup("pprof-http"); f != nil && f.Value.String() != "" {
+		return servePprof(f.Value.String())
+	}
+	return nil
+}
+
+// Context parses the command line and returns a context that is cancelled on interrupt.
+func Context() (context.Context, context.CancelFunc) {
+	Parse()
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+// Part runs a single part under the -timeout limit and logs the answer. If it
+// was stopped, the error should say how far the part got. Returns false if
+// there is no answer. f can report progress with ProgressFrom(ctx). Profiles
+// asked for on the command line cover just this part.
+func Part[T any](ctx context.Context, name string, f func(context.Context) (T, error)) (T, bool) {
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	progress := NewProgress(name)
+	ctx = context.WithValue(ctx, progressKey{}, progress)
+
+	stop, perr := profile(name)
+	if perr != nil {
+		log.Printf("%s: profiling: %v", name, perr)
+	}
+
+	start := time.Now()
+	answer, err := f(ctx)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	progress.Done()
+	if stop != nil {
+		if perr := stop(); perr != nil {
+			log.Printf("%s: profiling: %v", name, perr)
+		}
+	}
+
+	switch {
+	case err == nil:
+		log.Printf("%s: %v (%v)", name, answer, elapsed)
+		return answer, true
+	case errors.Is(err, context.DeadlineExceeded):
+		log.Printf("%s timed out after %v: %v", name, elapsed, err)
+	case errors.Is(err, context.Canceled):
+		log.Printf("%s cancelled after %v: %v", name, elapsed, err)
+	default:
+		log.Printf("%s failed: %v", name, err)
+	}
+	return answer, false
+}
@@ -0,0 +1,130 @@
+package aoc
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPart(t *testing.T) {
+	got, ok := Part(context.Background(), "answer", func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	if !ok || got != 42 {
+		t.Errorf("Part() = %v, %v, want 42, true", got, ok)
+	}
+}
+
+func TestParseOnce(t *testing.T) {
+	fs := flag.NewFlagSet("day", flag.ContinueOnError)
+	n := fs.Int("n", 0, "")
+	fs.String("pprof-http", "", "")
+	if err := parse(fs, []string{"-n", "3"}); err != nil {
+		t.Fatal(err)
+	}
+	// Days and Context both call Parse, the second call is a no-op.
+	if err := parse(fs, []string{"-n", "4", "-pprof-http", "localhost:0"}); err != nil {
+		t.Fatal(err)
+	}
+	if *n != 3 {
+		t.Errorf("-n = %v after parsing twice, want 3", *n)
+	}
+}
+
+func TestParseBadFlag(t *testing.T) {
+	fs := flag.NewFlagSet("day", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if err := parse(fs, []string{"-nope"}); err == nil {
+		t.Errorf("parse accepted an unknown flag")
+	}
+}
+
+func TestPartTimeout(t *testing.T) {
+	defer func(d time.Duration) { *timeout = d }(*timeout)
+	*timeout = 10 * time.Millisecond
+
+	_, ok := Part(context.Background(), "slow", func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if ok {
+		t.Errorf("Part() reported an answer after the timeout")
+	}
+}
+
+func TestProgressJSON(t *testing.T) {
+	var b strings.Builder
+	r := newReporter("part 1", &b, 0, writeJSON)
+	r.Step(3, 24, M("states", 10))
+	r.Done()
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d events, want 2: %q", len(lines), b.String())
+	}
+	e, ok := ParseEvent(lines[0])
+	if !ok {
+		t.Fatalf("ParseEvent(%q) failed", lines[0])
+	}
+	if e.Name != "part 1" || e.Step != 3 || e.Total != 24 || e.Done {
+		t.Errorf("first event = %+v", e)
+	}
+	if e, _ := ParseEvent(lines[1]); !e.Done {
+		t.Errorf("last event isn't done: %+v", e)
+	}
+	if _, ok := ParseEvent("2022/12/19 part 1: 33"); ok {
+		t.Errorf("ParseEvent accepted a log line")
+	}
+}
+
+func TestProgressLine(t *testing.T) {
+	var b strings.Builder
+	r := newReporter("rounds", &b, time.Hour, writeLine)
+	r.Step(1, 0, M("moving", 5), M("elves", 22))
+	r.Step(2, 0) // too soon, not drawn
+
+	want := "\r\033[Krounds: 1 elves=22 moving=5 (0s)"
+	if got := b.String(); got != want {
+		t.Errorf("status line = %q, want %q", got, want)
+	}
+}
+
+func TestProgressLineLog(t *testing.T) {
+	var b strings.Builder
+	r := newReporter("rounds", &b, time.Hour, writeLine)
+	fmt.Fprint(r, "before\n") // nothing to clear yet
+	r.Step(1, 0)
+	fmt.Fprint(r, "answer\n")
+	r.Done()
+	fmt.Fprint(r, "after\n")
+
+	want := "before\n" +
+		"\r\033[Krounds: 1 (0s)" +
+		"\r\033[Kanswer\n\r\033[Krounds: 1 (0s)" +
+		"\r\033[K" +
+		"after\n"
+	if got := b.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestTerminalLog(t *testing.T) {
+	prev := log.Writer()
+	var b strings.Builder
+	term := newTerminal(newReporter("rounds", &b, time.Hour, writeLine))
+	term.Step(1, 0)
+	log.Print("answer")
+	term.Done()
+
+	if got := b.String(); !strings.Contains(got, "\r\033[K") || !strings.Contains(got, "answer\n\r\033[Krounds: 1") {
+		t.Errorf("log output wasn't kept off the status line: %q", got)
+	}
+	if log.Writer() != prev {
+		t.Errorf("log output wasn't put back after Done")
+	}
+}
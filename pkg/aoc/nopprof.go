@@ -0,0 +1,9 @@
+//go:build !pprof
+
+package aoc
+
+import "errors"
+
+func servePprof(addr string) error {
+	return errors.New("built without pprof, use aoc run -pprof-http or go run -tags pprof")
+}
@@ -0,0 +1,18 @@
+//go:build !pprof
+
+package aoc
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestParseWithoutPprof(t *testing.T) {
+	fs := flag.NewFlagSet("day", flag.ContinueOnError)
+	fs.String("pprof-http", "", "")
+	err := parse(fs, []string{"-pprof-http", "localhost:0"})
+	if err == nil || !strings.Contains(err.Error(), "-tags pprof") {
+		t.Errorf("got: %v, want an error pointing at -tags pprof", err)
+	}
+}
@@ -0,0 +1,27 @@
+//go:build pprof
+
+package aoc
+
+import (
+	"log"
+	"net"
+	"net/http"
+	_ "net/http/pprof"
+	"strings"
+)
+
+// servePprof serves net/http/pprof on addr, a bare port is on localhost. It's
+// only built with -tags pprof, which aoc run -pprof-http adds, so the handlers
+// aren't registered in every day.
+func servePprof(addr string) error {
+	if strings.HasPrefix(addr, ":") {
+		addr = "localhost" + addr
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("pprof on http://%s/debug/pprof/", ln.Addr())
+	go http.Serve(ln, nil)
+	return nil
+}
@@ -0,0 +1,94 @@
+package aoc
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+)
+
+var (
+	cpuProfile = flag.String("cpuprofile", "", "write a CPU profile of each part into this directory")
+	memProfile = flag.String("memprofile", "", "write a heap profile after each part into this directory")
+	traceDir   = flag.String("trace", "", "write an execution trace of each part into this directory")
+	pprofHTTP  = flag.String("pprof-http", "", "serve live profiles on this address, like localhost:6060")
+)
+
+// profileName is the file for a part's profile, like day19-part-1.cpu.pprof.
+func profileName(dir, part, ext string) string {
+	day := strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	part = strings.ReplaceAll(strings.ToLower(part), " ", "-")
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.%s", day, part, ext))
+}
+
+func createProfile(dir, part, ext string) (*os.File, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return os.Create(profileName(dir, part, ext))
+}
+
+// profile starts the profiles asked for on the command line for a part. The
+// returned func stops them and writes them out.
+func profile(part string) (func() error, error) {
+	var stops []func() error
+	stop := func() error {
+		var first error
+		for i := len(stops) - 1; i >= 0; i-- {
+			if err := stops[i](); err != nil && first == nil {
+				first = err
+			}
+		}
+		return first
+	}
+
+	if *cpuProfile != "" {
+		f, err := createProfile(*cpuProfile, part, "cpu.pprof")
+		if err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+		stops = append(stops, func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		})
+	}
+
+	if *traceDir != "" {
+		f, err := createProfile(*traceDir, part, "trace")
+		if err != nil {
+			stop()
+			return nil, err
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			stop()
+			return nil, err
+		}
+		stops = append(stops, func() error {
+			trace.Stop()
+			return f.Close()
+		})
+	}
+
+	if *memProfile != "" {
+		stops = append(stops, func() error {
+			f, err := createProfile(*memProfile, part, "mem.pprof")
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			// Get up to date statistics.
+			runtime.GC()
+			return pprof.WriteHeapProfile(f)
+		})
+	}
+	return stop, nil
+}
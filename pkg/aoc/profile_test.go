@@ -0,0 +1,93 @@
+package aoc
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"runtime/trace"
+	"testing"
+)
+
+// setProfileFlags sets the profiling flags for a test, and puts them back
+// after.
+func setProfileFlags(t *testing.T, cpu, mem, tr string) {
+	old := []string{*cpuProfile, *memProfile, *traceDir}
+	t.Cleanup(func() {
+		*cpuProfile, *memProfile, *traceDir = old[0], old[1], old[2]
+	})
+	*cpuProfile, *memProfile, *traceDir = cpu, mem, tr
+}
+
+func TestProfileName(t *testing.T) {
+	defer func(arg string) { os.Args[0] = arg }(os.Args[0])
+
+	cases := []struct {
+		prog string
+		part string
+		want string
+	}{
+		{"/tmp/go-build123/b001/exe/day19", "Part 1", "prof/day19-part-1.cpu.pprof"},
+		{`day19.exe`, "part 2", "prof/day19-part-2.cpu.pprof"},
+		{"day24", "Going back", "prof/day24-going-back.cpu.pprof"},
+	}
+	for _, c := range cases {
+		os.Args[0] = c.prog
+		if got := profileName("prof", c.part, "cpu.pprof"); got != filepath.FromSlash(c.want) {
+			t.Errorf("%v %q got: %v want: %v", c.prog, c.part, got, c.want)
+		}
+	}
+}
+
+func TestProfile(t *testing.T) {
+	dir := t.TempDir()
+	setProfileFlags(t, dir, dir, dir)
+
+	stop, err := profile("Part 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !trace.IsEnabled() {
+		t.Errorf("trace wasn't started")
+	}
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ext := range []string{"cpu.pprof", "mem.pprof", "trace"} {
+		fi, err := os.Stat(profileName(dir, "Part 1", ext))
+		if err != nil {
+			t.Errorf("%v: %v", ext, err)
+		} else if fi.Size() == 0 {
+			t.Errorf("%v is empty", ext)
+		}
+	}
+	// Everything is stopped, so the next part can start again.
+	if trace.IsEnabled() {
+		t.Errorf("trace is still running")
+	}
+	if err := pprof.StartCPUProfile(io.Discard); err != nil {
+		t.Errorf("CPU profile is still running: %v", err)
+	}
+	pprof.StopCPUProfile()
+}
+
+func TestProfileStartFails(t *testing.T) {
+	dir := t.TempDir()
+	setProfileFlags(t, dir, "", dir)
+
+	// The trace can't start while another one is running, the CPU profile
+	// started before it has to be stopped again.
+	if err := trace.Start(io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	_, err := profile("Part 1")
+	trace.Stop()
+	if err == nil {
+		t.Fatalf("expected an error starting a second trace")
+	}
+	if err := pprof.StartCPUProfile(io.Discard); err != nil {
+		t.Errorf("CPU profile was left running: %v", err)
+	}
+	pprof.StopCPUProfile()
+}
@@ -0,0 +1,226 @@
+package aoc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress reports how far a long running loop has got.
+type Progress interface {
+	// Step records that step of total is done, total is 0 if it isn't known.
+	Step(step, total int, metrics ...Metric)
+	// Done clears the status, call it once the loop is finished.
+	Done()
+}
+
+// Metric is an extra named value shown alongside the step, like a state count.
+type Metric struct {
+	Name  string
+	Value any
+}
+
+// M is shorthand for a Metric.
+func M(name string, value any) Metric {
+	return Metric{Name: name, Value: value}
+}
+
+// Event is a progress update. When stderr isn't a terminal these are written
+// one per line as JSON, that's how the runner picks them up.
+type Event struct {
+	Day     string         `json:"day,omitempty"`
+	Name    string         `json:"progress"`
+	Step    int            `json:"step"`
+	Total   int            `json:"total,omitempty"`
+	Metrics map[string]any `json:"metrics,omitempty"`
+	Elapsed time.Duration  `json:"elapsed"`
+	Done    bool           `json:"done,omitempty"`
+}
+
+const eventPrefix = `{"progress":`
+
+// ParseEvent decodes a line written by a Progress, ok is false for any other
+// output.
+func ParseEvent(line string) (*Event, bool) {
+	if !strings.HasPrefix(line, eventPrefix) {
+		return nil, false
+	}
+	var e Event
+	if err := json.Unmarshal([]byte(line), &e); err != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+func (e *Event) String() string {
+	var b strings.Builder
+	if e.Day != "" {
+		fmt.Fprintf(&b, "%s ", e.Day)
+	}
+	fmt.Fprintf(&b, "%s: %d", e.Name, e.Step)
+	if e.Total > 0 {
+		fmt.Fprintf(&b, "/%d", e.Total)
+	}
+	for _, m := range e.order() {
+		fmt.Fprintf(&b, " %s=%v", m, e.Metrics[m])
+	}
+	fmt.Fprintf(&b, " (%v)", e.Elapsed.Round(time.Second))
+	return b.String()
+}
+
+// order is the metric names sorted, so the status line doesn't jump around.
+func (e *Event) order() []string {
+	names := make([]string, 0, len(e.Metrics))
+	for k := range e.Metrics {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsTerminal reports if f looks like an interactive terminal.
+func IsTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// NewProgress returns a Progress for name. On a terminal it keeps a single
+// status line up to date, otherwise it writes a JSON Event every second.
+func NewProgress(name string) Progress {
+	if IsTerminal(os.Stderr) {
+		r := newReporter(name, os.Stderr, 100*time.Millisecond, writeLine)
+		return newTerminal(r)
+	}
+	return newReporter(name, os.Stderr, time.Second, writeJSON)
+}
+
+// terminal is a status line on stderr, which log writes to as well. Until
+// Done, log output goes through the reporter so it doesn't end up on the
+// same line as the status.
+type terminal struct {
+	*reporter
+	prev io.Writer
+}
+
+func newTerminal(r *reporter) *terminal {
+	t := &terminal{reporter: r, prev: log.Writer()}
+	log.SetOutput(r)
+	return t
+}
+
+func (t *terminal) Done() {
+	t.reporter.Done()
+	log.SetOutput(t.prev)
+}
+
+type reporter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	every  time.Duration
+	render func(w io.Writer, e *Event)
+	start  time.Time
+	last   time.Time
+	event  Event
+}
+
+func newReporter(name string, w io.Writer, every time.Duration, render func(io.Writer, *Event)) *reporter {
+	return &reporter{
+		w:      w,
+		every:  every,
+		render: render,
+		start:  time.Now(),
+		event:  Event{Name: name},
+	}
+}
+
+func (r *reporter) Step(step, total int, metrics ...Metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.event.Step = step
+	r.event.Total = total
+	if len(metrics) > 0 {
+		r.event.Metrics = make(map[string]any, len(metrics))
+		for _, m := range metrics {
+			r.event.Metrics[m.Name] = m.Value
+		}
+	}
+
+	now := time.Now()
+	if now.Sub(r.last) < r.every {
+		return
+	}
+	r.last = now
+	r.event.Elapsed = now.Sub(r.start)
+	r.render(r.w, &r.event)
+}
+
+func (r *reporter) Done() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Only say anything if there was a status to begin with.
+	if r.last.IsZero() {
+		return
+	}
+	r.event.Done = true
+	r.event.Elapsed = time.Since(r.start)
+	r.render(r.w, &r.event)
+}
+
+// Write clears the status line, writes p and then draws the status again
+// underneath, so other output can share the terminal.
+func (r *reporter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	showing := !r.last.IsZero() && !r.event.Done
+	if showing {
+		fmt.Fprint(r.w, "\r\033[K")
+	}
+	n, err := r.w.Write(p)
+	if showing {
+		r.render(r.w, &r.event)
+	}
+	return n, err
+}
+
+// writeLine redraws the status line in place, and clears it when done.
+func writeLine(w io.Writer, e *Event) {
+	if e.Done {
+		fmt.Fprint(w, "\r\033[K")
+		return
+	}
+	fmt.Fprintf(w, "\r\033[K%s", e)
+}
+
+func writeJSON(w io.Writer, e *Event) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	w.Write(append(b, '\n'))
+}
+
+type nopProgress struct{}
+
+func (nopProgress) Step(int, int, ...Metric) {}
+func (nopProgress) Done()                    {}
+
+type progressKey struct{}
+
+// ProgressFrom returns the Progress for the part running with ctx, it
+// discards everything outside of Part.
+func ProgressFrom(ctx context.Context) Progress {
+	if p, ok := ctx.Value(progressKey{}).(Progress); ok {
+		return p
+	}
+	return nopProgress{}
+}
@@ -0,0 +1,43 @@
+package aoc
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+)
+
+// Solver is a day's solution, each part gets the whole puzzle input.
+type Solver interface {
+	Part1(ctx context.Context, input string) (any, error)
+	Part2(ctx context.Context, input string) (any, error)
+}
+
+// Main reads the puzzle input from stdin and runs both parts of s, with the
+// same flags as Context and Part.
+func Main(s Solver) {
+	ctx, cancel := Context()
+	defer cancel()
+
+	if err := solve(ctx, s, os.Stdin); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// solve runs both parts of s on everything in r. A part that fails is
+// logged by Part, the other part still runs.
+func solve(ctx context.Context, s Solver, r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	input := string(b)
+
+	Part(ctx, "part 1", func(ctx context.Context) (any, error) {
+		return s.Part1(ctx, input)
+	})
+	Part(ctx, "part 2", func(ctx context.Context) (any, error) {
+		return s.Part2(ctx, input)
+	})
+	return nil
+}
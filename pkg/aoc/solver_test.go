@@ -0,0 +1,46 @@
+package aoc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"testing"
+)
+
+type testSolver struct {
+	inputs []string
+}
+
+func (s *testSolver) Part1(ctx context.Context, input string) (any, error) {
+	s.inputs = append(s.inputs, input)
+	return len(strings.Fields(input)), nil
+}
+
+func (s *testSolver) Part2(ctx context.Context, input string) (any, error) {
+	s.inputs = append(s.inputs, input)
+	return nil, errors.New("not solved yet")
+}
+
+func TestSolve(t *testing.T) {
+	var out strings.Builder
+	defer func(w io.Writer, flags int) {
+		log.SetOutput(w)
+		log.SetFlags(flags)
+	}(log.Writer(), log.Flags())
+	log.SetOutput(&out)
+	log.SetFlags(0)
+
+	s := &testSolver{}
+	if err := solve(context.Background(), s, strings.NewReader("1 2\n3\n")); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.inputs) != 2 || s.inputs[0] != "1 2\n3\n" || s.inputs[1] != s.inputs[0] {
+		t.Errorf("each part should get the whole input, got: %q", s.inputs)
+	}
+	got := out.String()
+	if !strings.HasPrefix(got, "part 1: 3 (") || !strings.Contains(got, "part 2 failed: not solved yet") {
+		t.Errorf("wrong answers logged: %q", got)
+	}
+}
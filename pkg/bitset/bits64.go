@@ -0,0 +1,105 @@
+package bitset
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// Bits64 is a fixed size set of the integers 0-63, as a value type.
+type Bits64 uint64
+
+func Of(idx ...int) Bits64 {
+	var b Bits64
+	for _, i := range idx {
+		b = b.Set(i)
+	}
+	return b
+}
+
+// Full returns a set with 0 through n-1.
+func Full(n int) Bits64 {
+	if n >= 64 {
+		return ^Bits64(0)
+	}
+	return Bits64(1)<<n - 1
+}
+
+func (b Bits64) Set(i int) Bits64 {
+	return b | 1<<i
+}
+
+func (b Bits64) Clear(i int) Bits64 {
+	return b &^ (1 << i)
+}
+
+func (b Bits64) Test(i int) bool {
+	return b&(1<<i) != 0
+}
+
+func (b Bits64) Count() int {
+	return bits.OnesCount64(uint64(b))
+}
+
+func (b Bits64) Empty() bool {
+	return b == 0
+}
+
+func (b Bits64) And(o Bits64) Bits64 {
+	return b & o
+}
+
+func (b Bits64) Or(o Bits64) Bits64 {
+	return b | o
+}
+
+func (b Bits64) Xor(o Bits64) Bits64 {
+	return b ^ o
+}
+
+func (b Bits64) AndNot(o Bits64) Bits64 {
+	return b &^ o
+}
+
+// Disjoint returns true if the two sets have nothing in common.
+func (b Bits64) Disjoint(o Bits64) bool {
+	return b&o == 0
+}
+
+// SubsetOf returns true if every member of b is also in o.
+func (b Bits64) SubsetOf(o Bits64) bool {
+	return b&^o == 0
+}
+
+// Each calls f with every member, smallest first.
+func (b Bits64) Each(f func(i int)) {
+	for b != 0 {
+		i := bits.TrailingZeros64(uint64(b))
+		f(i)
+		b &= b - 1
+	}
+}
+
+func (b Bits64) Indices() []int {
+	out := make([]int, 0, b.Count())
+	b.Each(func(i int) { out = append(out, i) })
+	return out
+}
+
+// Subsets calls f with every subset of b, including the empty set and b itself.
+func (b Bits64) Subsets(f func(s Bits64)) {
+	s := b
+	for {
+		f(s)
+		if s == 0 {
+			return
+		}
+		s = (s - 1) & b
+	}
+}
+
+func (b Bits64) String() string {
+	parts := make([]string, 0, b.Count())
+	b.Each(func(i int) { parts = append(parts, fmt.Sprint(i)) })
+	return fmt.Sprintf("{%s}", strings.Join(parts, ","))
+}
@@ -0,0 +1,126 @@
+package bitset
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// BitSet is a set of non-negative integers that grows as needed.
+type BitSet struct {
+	words []uint64
+}
+
+func New(size int) *BitSet {
+	return &BitSet{
+		words: make([]uint64, (size+63)/64),
+	}
+}
+
+func (b *BitSet) Clone() *BitSet {
+	words := make([]uint64, len(b.words))
+	copy(words, b.words)
+	return &BitSet{words: words}
+}
+
+func (b *BitSet) Set(i int) {
+	w := i / 64
+	for w >= len(b.words) {
+		b.words = append(b.words, 0)
+	}
+	b.words[w] |= 1 << (i % 64)
+}
+
+func (b *BitSet) Clear(i int) {
+	if w := i / 64; w < len(b.words) {
+		b.words[w] &^= 1 << (i % 64)
+	}
+}
+
+func (b *BitSet) Test(i int) bool {
+	w := i / 64
+	return w < len(b.words) && b.words[w]&(1<<(i%64)) != 0
+}
+
+func (b *BitSet) Count() int {
+	c := 0
+	for _, w := range b.words {
+		c += bits.OnesCount64(w)
+	}
+	return c
+}
+
+func (b *BitSet) Empty() bool {
+	for _, w := range b.words {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *BitSet) Equals(o *BitSet) bool {
+	for i := 0; i < len(b.words) || i < len(o.words); i++ {
+		if b.word(i) != o.word(i) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *BitSet) And(o *BitSet) *BitSet {
+	return b.combine(o, func(x, y uint64) uint64 { return x & y })
+}
+
+func (b *BitSet) Or(o *BitSet) *BitSet {
+	return b.combine(o, func(x, y uint64) uint64 { return x | y })
+}
+
+func (b *BitSet) Xor(o *BitSet) *BitSet {
+	return b.combine(o, func(x, y uint64) uint64 { return x ^ y })
+}
+
+func (b *BitSet) AndNot(o *BitSet) *BitSet {
+	return b.combine(o, func(x, y uint64) uint64 { return x &^ y })
+}
+
+// Each calls f with every member, smallest first.
+func (b *BitSet) Each(f func(i int)) {
+	for wi, w := range b.words {
+		for w != 0 {
+			f(wi*64 + bits.TrailingZeros64(w))
+			w &= w - 1
+		}
+	}
+}
+
+func (b *BitSet) Indices() []int {
+	out := make([]int, 0, b.Count())
+	b.Each(func(i int) { out = append(out, i) })
+	return out
+}
+
+func (b *BitSet) String() string {
+	parts := make([]string, 0, b.Count())
+	b.Each(func(i int) { parts = append(parts, fmt.Sprint(i)) })
+	return fmt.Sprintf("{%s}", strings.Join(parts, ","))
+}
+
+func (b *BitSet) word(i int) uint64 {
+	if i < len(b.words) {
+		return b.words[i]
+	}
+	return 0
+}
+
+func (b *BitSet) combine(o *BitSet, f func(x, y uint64) uint64) *BitSet {
+	n := len(b.words)
+	if len(o.words) > n {
+		n = len(o.words)
+	}
+	out := &BitSet{words: make([]uint64, n)}
+	for i := range out.words {
+		out.words[i] = f(b.word(i), o.word(i))
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package bitset_test
+
+import (
+	"testing"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/bitset"
+)
+
+func TestBits64(t *testing.T) {
+	a := bitset.Of(0, 3, 63)
+	b := bitset.Of(3, 5)
+
+	if !a.Test(63) || a.Test(1) || a.Count() != 3 {
+		t.Errorf("basic ops wrong: %v", a)
+	}
+	if got := a.And(b).String(); got != "{3}" {
+		t.Errorf("and wrong, got: %v", got)
+	}
+	if got := a.Xor(b).String(); got != "{0,5,63}" {
+		t.Errorf("xor wrong, got: %v", got)
+	}
+	if a.Disjoint(b) || !a.Clear(3).Disjoint(b) {
+		t.Errorf("disjoint wrong")
+	}
+	if !bitset.Of(3).SubsetOf(a) || b.SubsetOf(a) {
+		t.Errorf("subset wrong")
+	}
+	if bitset.Full(64).Count() != 64 || bitset.Full(5) != bitset.Of(0, 1, 2, 3, 4) {
+		t.Errorf("full wrong")
+	}
+
+	seen := make(map[bitset.Bits64]bool)
+	bitset.Of(1, 4, 6).Subsets(func(s bitset.Bits64) { seen[s] = true })
+	if len(seen) != 8 || !seen[0] || !seen[bitset.Of(1, 6)] {
+		t.Errorf("subsets wrong, got: %v", seen)
+	}
+}
+
+func TestBitSet(t *testing.T) {
+	a := bitset.New(10)
+	a.Set(1)
+	a.Set(200)
+	b := bitset.New(0)
+	b.Set(200)
+	b.Set(7)
+
+	if !a.Test(200) || a.Test(199) || a.Test(5000) || a.Count() != 2 {
+		t.Errorf("basic ops wrong: %v", a)
+	}
+	if got := a.And(b).String(); got != "{200}" {
+		t.Errorf("and wrong, got: %v", got)
+	}
+	if got := a.Or(b).String(); got != "{1,7,200}" {
+		t.Errorf("or wrong, got: %v", got)
+	}
+	if got := a.AndNot(b).String(); got != "{1}" {
+		t.Errorf("and not wrong, got: %v", got)
+	}
+
+	c := a.Clone()
+	c.Clear(200)
+	if !a.Test(200) || c.Test(200) || a.Equals(c) {
+		t.Errorf("clone should be independent")
+	}
+	c.Set(200)
+	if !a.Equals(c) || !a.Xor(c).Empty() {
+		t.Errorf("should be equal")
+	}
+}
@@ -0,0 +1,90 @@
+package collections_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/collections"
+)
+
+func sorted(in []int) []int {
+	sort.Ints(in)
+	return in
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSet(t *testing.T) {
+	a := collections.NewSet(1, 2, 3, 4)
+	b := collections.NewSet(3, 4, 5)
+
+	if got := sorted(a.Union(b).Values()); !equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("union wrong, got: %v", got)
+	}
+	if got := sorted(a.Intersect(b).Values()); !equal(got, []int{3, 4}) {
+		t.Errorf("intersect wrong, got: %v", got)
+	}
+	if got := sorted(a.Difference(b).Values()); !equal(got, []int{1, 2}) {
+		t.Errorf("difference wrong, got: %v", got)
+	}
+	if a.Disjoint(b) || !a.Disjoint(collections.NewSet(9)) {
+		t.Errorf("disjoint wrong")
+	}
+}
+
+func TestCounter(t *testing.T) {
+	c := collections.NewCounter("a", "b", "a", "c", "a", "b")
+	if c.Get("a") != 3 || c.Get("z") != 0 || c.Total() != 6 {
+		t.Errorf("counts wrong: %v", c)
+	}
+	if got := c.MostCommon(2); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("most common wrong, got: %v", got)
+	}
+}
+
+func TestStack(t *testing.T) {
+	s := collections.NewStack[int]()
+	for i := 0; i < 5; i++ {
+		s.Push(i)
+	}
+	c := s.Clone()
+	for i := 4; i >= 0; i-- {
+		if v := s.Pop(); v != i {
+			t.Errorf("pop want: %v got: %v", i, v)
+		}
+	}
+	if !s.Empty() || c.Len() != 5 || c.Peek() != 4 {
+		t.Errorf("clone should be independent")
+	}
+}
+
+func TestDeque(t *testing.T) {
+	d := collections.NewDeque[int](2)
+	for i := 0; i < 5; i++ {
+		d.PushBack(i)
+	}
+	d.PushFront(-1)
+	if got := d.Values(); !equal(got, []int{-1, 0, 1, 2, 3, 4}) {
+		t.Errorf("values wrong, got: %v", got)
+	}
+	if d.PopFront() != -1 || d.PopBack() != 4 || d.Front() != 0 || d.Back() != 3 || d.Len() != 4 {
+		t.Errorf("pop wrong, left: %v", d.Values())
+	}
+	// wrap around the ring several times
+	for i := 0; i < 10; i++ {
+		d.PushBack(d.PopFront())
+	}
+	if got := d.Values(); !equal(got, []int{2, 3, 0, 1}) {
+		t.Errorf("rotate wrong, got: %v", got)
+	}
+}
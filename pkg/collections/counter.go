@@ -0,0 +1,49 @@
+package collections
+
+import "sort"
+
+type Counter[T comparable] map[T]int
+
+func NewCounter[T comparable](vals ...T) Counter[T] {
+	c := make(Counter[T])
+	for _, v := range vals {
+		c[v]++
+	}
+	return c
+}
+
+func (c Counter[T]) Add(v T) int {
+	c[v]++
+	return c[v]
+}
+
+func (c Counter[T]) AddN(v T, n int) int {
+	c[v] += n
+	return c[v]
+}
+
+func (c Counter[T]) Get(v T) int {
+	return c[v]
+}
+
+// Total is the sum of all counts.
+func (c Counter[T]) Total() int {
+	t := 0
+	for _, n := range c {
+		t += n
+	}
+	return t
+}
+
+// MostCommon returns up to n keys, highest count first. Ties are in no particular order.
+func (c Counter[T]) MostCommon(n int) []T {
+	keys := make([]T, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return c[keys[i]] > c[keys[j]] })
+	if n < len(keys) {
+		keys = keys[0:n]
+	}
+	return keys
+}
@@ -0,0 +1,103 @@
+package collections
+
+// Deque is a double ended queue backed by a ring buffer that grows as needed.
+type Deque[T any] struct {
+	buffer []T
+	head   int
+	size   int
+}
+
+func NewDeque[T any](capacity int) *Deque[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Deque[T]{
+		buffer: make([]T, capacity),
+	}
+}
+
+func (d *Deque[T]) Len() int {
+	return d.size
+}
+
+func (d *Deque[T]) Empty() bool {
+	return d.size == 0
+}
+
+func (d *Deque[T]) PushBack(v T) {
+	d.grow()
+	d.buffer[d.index(d.size)] = v
+	d.size++
+}
+
+func (d *Deque[T]) PushFront(v T) {
+	d.grow()
+	d.head = d.index(len(d.buffer) - 1)
+	d.buffer[d.head] = v
+	d.size++
+}
+
+func (d *Deque[T]) PopFront() T {
+	if d.size == 0 {
+		panic("PopFront on empty deque")
+	}
+	var zero T
+	v := d.buffer[d.head]
+	d.buffer[d.head] = zero
+	d.head = d.index(1)
+	d.size--
+	return v
+}
+
+func (d *Deque[T]) PopBack() T {
+	if d.size == 0 {
+		panic("PopBack on empty deque")
+	}
+	var zero T
+	i := d.index(d.size - 1)
+	v := d.buffer[i]
+	d.buffer[i] = zero
+	d.size--
+	return v
+}
+
+func (d *Deque[T]) Front() T {
+	return d.At(0)
+}
+
+func (d *Deque[T]) Back() T {
+	return d.At(d.size - 1)
+}
+
+// At returns the i-th element counting from the front.
+func (d *Deque[T]) At(i int) T {
+	if i < 0 || i >= d.size {
+		panic("deque index out of range")
+	}
+	return d.buffer[d.index(i)]
+}
+
+// Values returns the contents from front to back.
+func (d *Deque[T]) Values() []T {
+	out := make([]T, d.size)
+	for i := range out {
+		out[i] = d.buffer[d.index(i)]
+	}
+	return out
+}
+
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) % len(d.buffer)
+}
+
+func (d *Deque[T]) grow() {
+	if d.size < len(d.buffer) {
+		return
+	}
+	buffer := make([]T, len(d.buffer)*2)
+	for i := 0; i < d.size; i++ {
+		buffer[i] = d.buffer[d.index(i)]
+	}
+	d.buffer = buffer
+	d.head = 0
+}
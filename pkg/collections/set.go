@@ -0,0 +1,93 @@
+package collections
+
+type Set[T comparable] map[T]struct{}
+
+func NewSet[T comparable](vals ...T) Set[T] {
+	s := make(Set[T], len(vals))
+	for _, v := range vals {
+		s[v] = struct{}{}
+	}
+	return s
+}
+
+func (s Set[T]) Add(vals ...T) {
+	for _, v := range vals {
+		s[v] = struct{}{}
+	}
+}
+
+func (s Set[T]) Remove(v T) {
+	delete(s, v)
+}
+
+func (s Set[T]) Contains(v T) bool {
+	_, ok := s[v]
+	return ok
+}
+
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+func (s Set[T]) Clone() Set[T] {
+	c := make(Set[T], len(s))
+	for k := range s {
+		c[k] = struct{}{}
+	}
+	return c
+}
+
+// Values returns the members in no particular order.
+func (s Set[T]) Values() []T {
+	out := make([]T, 0, len(s))
+	for k := range s {
+		out = append(out, k)
+	}
+	return out
+}
+
+func (s Set[T]) Union(o Set[T]) Set[T] {
+	u := s.Clone()
+	for k := range o {
+		u[k] = struct{}{}
+	}
+	return u
+}
+
+func (s Set[T]) Intersect(o Set[T]) Set[T] {
+	small, large := s, o
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	i := make(Set[T])
+	for k := range small {
+		if large.Contains(k) {
+			i[k] = struct{}{}
+		}
+	}
+	return i
+}
+
+func (s Set[T]) Difference(o Set[T]) Set[T] {
+	d := make(Set[T])
+	for k := range s {
+		if !o.Contains(k) {
+			d[k] = struct{}{}
+		}
+	}
+	return d
+}
+
+// Disjoint returns true if the two sets share no members.
+func (s Set[T]) Disjoint(o Set[T]) bool {
+	small, large := s, o
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	for k := range small {
+		if large.Contains(k) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package collections
+
+type Stack[T any] struct {
+	data []T
+}
+
+func NewStack[T any]() *Stack[T] {
+	return &Stack[T]{
+		data: make([]T, 0),
+	}
+}
+
+func (s *Stack[T]) Clone() *Stack[T] {
+	data := make([]T, len(s.data))
+	copy(data, s.data)
+	return &Stack[T]{
+		data: data,
+	}
+}
+
+func (s *Stack[T]) Peek() T {
+	return s.data[len(s.data)-1]
+}
+
+func (s *Stack[T]) Empty() bool {
+	return len(s.data) == 0
+}
+
+func (s *Stack[T]) Len() int {
+	return len(s.data)
+}
+
+func (s *Stack[T]) Push(v T) {
+	s.data = append(s.data, v)
+}
+
+func (s *Stack[T]) Pop() T {
+	r := s.data[len(s.data)-1]
+	var zero T
+	s.data[len(s.data)-1] = zero
+	s.data = s.data[0 : len(s.data)-1]
+	return r
+}
+
+// Values returns the contents from bottom to top.
+func (s *Stack[T]) Values() []T {
+	out := make([]T, len(s.data))
+	copy(out, s.data)
+	return out
+}
@@ -0,0 +1,134 @@
+package dsu
+
+// DSU is a disjoint set (union-find) over arbitrary comparable values, with
+// path compression and union by size. Values are added by Add or Union, the
+// lookups don't add anything and report values they haven't seen.
+type DSU[T comparable] struct {
+	index  map[T]int
+	values []T
+	parent []int
+	size   []int
+	count  int
+}
+
+func New[T comparable]() *DSU[T] {
+	return &DSU[T]{
+		index:  make(map[T]int),
+		values: make([]T, 0),
+		parent: make([]int, 0),
+		size:   make([]int, 0),
+	}
+}
+
+// Add makes v a singleton set if it isn't already known.
+func (d *DSU[T]) Add(v T) {
+	d.id(v)
+}
+
+func (d *DSU[T]) Contains(v T) bool {
+	_, ok := d.index[v]
+	return ok
+}
+
+// Len is the number of values, Count is the number of components.
+func (d *DSU[T]) Len() int {
+	return len(d.values)
+}
+
+func (d *DSU[T]) Count() int {
+	return d.count
+}
+
+// Find returns the representative value for the component v is in, ok is
+// false if v hasn't been added.
+func (d *DSU[T]) Find(v T) (T, bool) {
+	i, ok := d.index[v]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return d.values[d.find(i)], true
+}
+
+// Union merges the components of a and b, adding either one if it's new.
+// Returns false if they were already joined.
+func (d *DSU[T]) Union(a, b T) bool {
+	ra, rb := d.find(d.id(a)), d.find(d.id(b))
+	if ra == rb {
+		return false
+	}
+	if d.size[ra] < d.size[rb] {
+		ra, rb = rb, ra
+	}
+	d.parent[rb] = ra
+	d.size[ra] += d.size[rb]
+	d.count--
+	return true
+}
+
+// Connected reports if a and b are in the same component, it's false if
+// either one hasn't been added.
+func (d *DSU[T]) Connected(a, b T) bool {
+	ia, ok := d.index[a]
+	if !ok {
+		return false
+	}
+	ib, ok := d.index[b]
+	if !ok {
+		return false
+	}
+	return d.find(ia) == d.find(ib)
+}
+
+// Size returns the number of values in v's component, 0 if v hasn't been
+// added.
+func (d *DSU[T]) Size(v T) int {
+	i, ok := d.index[v]
+	if !ok {
+		return 0
+	}
+	return d.size[d.find(i)]
+}
+
+// Components returns every component, values within a component are in the
+// order they were added.
+func (d *DSU[T]) Components() [][]T {
+	byRoot := make(map[int]int)
+	out := make([][]T, 0, d.count)
+	for i, v := range d.values {
+		r := d.find(i)
+		ci, ok := byRoot[r]
+		if !ok {
+			ci = len(out)
+			byRoot[r] = ci
+			out = append(out, make([]T, 0, d.size[r]))
+		}
+		out[ci] = append(out[ci], v)
+	}
+	return out
+}
+
+func (d *DSU[T]) id(v T) int {
+	if i, ok := d.index[v]; ok {
+		return i
+	}
+	i := len(d.values)
+	d.index[v] = i
+	d.values = append(d.values, v)
+	d.parent = append(d.parent, i)
+	d.size = append(d.size, 1)
+	d.count++
+	return i
+}
+
+func (d *DSU[T]) find(i int) int {
+	root := i
+	for d.parent[root] != root {
+		root = d.parent[root]
+	}
+	// path compression
+	for d.parent[i] != root {
+		d.parent[i], i = root, d.parent[i]
+	}
+	return root
+}
@@ -0,0 +1,56 @@
+package dsu_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/dsu"
+)
+
+func TestDSU(t *testing.T) {
+	d := dsu.New[string]()
+	for _, v := range []string{"a", "b", "c", "d", "e"} {
+		d.Add(v)
+	}
+	if d.Count() != 5 {
+		t.Errorf("count want: 5 got: %v", d.Count())
+	}
+
+	d.Union("a", "b")
+	d.Union("c", "d")
+	if !d.Union("b", "d") {
+		t.Errorf("b and d should not have been connected yet")
+	}
+	if d.Union("a", "c") {
+		t.Errorf("a and c should already be connected")
+	}
+
+	if !d.Connected("a", "d") || d.Connected("a", "e") {
+		t.Errorf("connectivity wrong")
+	}
+	if d.Size("c") != 4 || d.Size("e") != 1 || d.Count() != 2 {
+		t.Errorf("sizes wrong, c: %v e: %v count: %v", d.Size("c"), d.Size("e"), d.Count())
+	}
+	if got := fmt.Sprint(d.Components()); got != "[[a b c d] [e]]" {
+		t.Errorf("components wrong, got: %v", got)
+	}
+
+	// lookups don't add unseen values.
+	if v, ok := d.Find("z"); ok || v != "" {
+		t.Errorf("find of an unseen value got: %q, %v", v, ok)
+	}
+	if d.Connected("z", "z") || d.Connected("a", "z") || d.Size("z") != 0 {
+		t.Errorf("unseen values should be in no component")
+	}
+	if d.Len() != 5 || d.Count() != 2 || d.Contains("z") {
+		t.Errorf("lookups added values, len: %v count: %v", d.Len(), d.Count())
+	}
+	if root, ok := d.Find("d"); !ok || !d.Connected(root, "a") {
+		t.Errorf("find d got: %q, %v", root, ok)
+	}
+
+	// union adds values on first use.
+	if !d.Union("e", "z") || d.Size("z") != 2 || d.Len() != 6 {
+		t.Errorf("union should add new values, size: %v len: %v", d.Size("z"), d.Len())
+	}
+}
@@ -0,0 +1,58 @@
+// Package interval has closed integer intervals and sets of them.
+package interval
+
+import (
+	"fmt"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/mathaid"
+)
+
+// Interval is every integer from Low to High, both included.
+type Interval[T mathaid.Integer] struct {
+	Low  T
+	High T
+}
+
+func New[T mathaid.Integer](low, high T) Interval[T] {
+	return Interval[T]{Low: low, High: high}
+}
+
+// Empty is true if High is below Low.
+func (i Interval[T]) Empty() bool {
+	return i.High < i.Low
+}
+
+// Len is how many integers are in the interval.
+func (i Interval[T]) Len() T {
+	if i.Empty() {
+		return 0
+	}
+	return i.High - i.Low + 1
+}
+
+func (i Interval[T]) Has(v T) bool {
+	return i.Low <= v && v <= i.High
+}
+
+// Contains is true if all of o is inside i.
+func (i Interval[T]) Contains(o Interval[T]) bool {
+	return i.Low <= o.Low && i.High >= o.High
+}
+
+// Overlaps is true if i and o have at least one integer in common.
+func (i Interval[T]) Overlaps(o Interval[T]) bool {
+	return i.Low <= o.High && o.Low <= i.High
+}
+
+// touches is true if the union of i and o is a single interval.
+func (i Interval[T]) touches(o Interval[T]) bool {
+	return i.Overlaps(o) || (i.High < o.Low && i.High+1 == o.Low) || (o.High < i.Low && o.High+1 == i.Low)
+}
+
+func (i Interval[T]) Intersect(o Interval[T]) Interval[T] {
+	return Interval[T]{Low: mathaid.Max(i.Low, o.Low), High: mathaid.Min(i.High, o.High)}
+}
+
+func (i Interval[T]) String() string {
+	return fmt.Sprintf("%v-%v", i.Low, i.High)
+}
@@ -0,0 +1,129 @@
+package interval
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/mathaid"
+)
+
+// IntervalSet is a union of intervals, kept as sorted disjoint spans. Spans
+// that overlap or touch are merged as they're inserted.
+type IntervalSet[T mathaid.Integer] struct {
+	spans []Interval[T]
+}
+
+func NewSet[T mathaid.Integer](ivs ...Interval[T]) *IntervalSet[T] {
+	s := &IntervalSet[T]{spans: make([]Interval[T], 0, len(ivs))}
+	for _, iv := range ivs {
+		s.Insert(iv)
+	}
+	return s
+}
+
+func (s *IntervalSet[T]) Clone() *IntervalSet[T] {
+	return &IntervalSet[T]{spans: append([]Interval[T](nil), s.spans...)}
+}
+
+// Insert adds every integer in iv.
+func (s *IntervalSet[T]) Insert(iv Interval[T]) {
+	if iv.Empty() {
+		return
+	}
+	// First span that could merge with iv, everything before ends too early.
+	lo := sort.Search(len(s.spans), func(i int) bool {
+		return s.spans[i].High >= iv.Low || s.spans[i].touches(iv)
+	})
+	hi := lo
+	for hi < len(s.spans) && s.spans[hi].touches(iv) {
+		iv.Low = mathaid.Min(iv.Low, s.spans[hi].Low)
+		iv.High = mathaid.Max(iv.High, s.spans[hi].High)
+		hi++
+	}
+
+	if lo == hi {
+		s.spans = append(s.spans, Interval[T]{})
+		copy(s.spans[lo+1:], s.spans[lo:])
+		s.spans[lo] = iv
+		return
+	}
+	s.spans[lo] = iv
+	s.spans = append(s.spans[:lo+1], s.spans[hi:]...)
+}
+
+// Merge adds everything in o.
+func (s *IntervalSet[T]) Merge(o *IntervalSet[T]) {
+	for _, iv := range o.spans {
+		s.Insert(iv)
+	}
+}
+
+// Subtract removes every integer in iv.
+func (s *IntervalSet[T]) Subtract(iv Interval[T]) {
+	if iv.Empty() {
+		return
+	}
+	out := make([]Interval[T], 0, len(s.spans)+1)
+	for _, sp := range s.spans {
+		if !sp.Overlaps(iv) {
+			out = append(out, sp)
+			continue
+		}
+		if sp.Low < iv.Low {
+			out = append(out, Interval[T]{Low: sp.Low, High: iv.Low - 1})
+		}
+		if sp.High > iv.High {
+			out = append(out, Interval[T]{Low: iv.High + 1, High: sp.High})
+		}
+	}
+	s.spans = out
+}
+
+// Complement is everything in bounds that isn't in the set.
+func (s *IntervalSet[T]) Complement(bounds Interval[T]) *IntervalSet[T] {
+	c := NewSet(bounds)
+	for _, sp := range s.spans {
+		c.Subtract(sp)
+	}
+	return c
+}
+
+// Gaps are the holes between the first and last spans.
+func (s *IntervalSet[T]) Gaps() []Interval[T] {
+	gaps := make([]Interval[T], 0)
+	for i := 1; i < len(s.spans); i++ {
+		gaps = append(gaps, Interval[T]{Low: s.spans[i-1].High + 1, High: s.spans[i].Low - 1})
+	}
+	return gaps
+}
+
+// Len is the total number of integers covered.
+func (s *IntervalSet[T]) Len() T {
+	var total T
+	for _, sp := range s.spans {
+		total += sp.Len()
+	}
+	return total
+}
+
+func (s *IntervalSet[T]) Has(v T) bool {
+	i := sort.Search(len(s.spans), func(i int) bool { return s.spans[i].High >= v })
+	return i < len(s.spans) && s.spans[i].Has(v)
+}
+
+func (s *IntervalSet[T]) Empty() bool {
+	return len(s.spans) == 0
+}
+
+// Intervals returns the spans in order.
+func (s *IntervalSet[T]) Intervals() []Interval[T] {
+	return append([]Interval[T](nil), s.spans...)
+}
+
+func (s *IntervalSet[T]) String() string {
+	parts := make([]string, len(s.spans))
+	for i, sp := range s.spans {
+		parts[i] = sp.String()
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
@@ -0,0 +1,93 @@
+package interval_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/interval"
+)
+
+func TestInterval(t *testing.T) {
+	a := interval.New(2, 6)
+	b := interval.New(4, 8)
+	if !a.Overlaps(b) || !b.Overlaps(a) {
+		t.Errorf("%v and %v should overlap", a, b)
+	}
+	if a.Overlaps(interval.New(7, 9)) {
+		t.Errorf("%v and 7-9 shouldn't overlap", a)
+	}
+	if !interval.New(2, 8).Contains(interval.New(3, 7)) {
+		t.Errorf("2-8 should contain 3-7")
+	}
+	if got := a.Intersect(b); got != interval.New(4, 6) {
+		t.Errorf("wrong intersection, got: %v", got)
+	}
+	if a.Len() != 5 || interval.New(3, 2).Len() != 0 {
+		t.Errorf("wrong lengths, got: %v %v", a.Len(), interval.New(3, 2).Len())
+	}
+}
+
+func TestInsert(t *testing.T) {
+	s := interval.NewSet(interval.New(10, 12), interval.New(1, 3), interval.New(20, 25))
+	if got := s.String(); got != "{1-3, 10-12, 20-25}" {
+		t.Fatalf("wrong spans, got: %v", got)
+	}
+
+	cases := []struct {
+		iv   interval.Interval[int]
+		want string
+	}{
+		{interval.New(5, 6), "{1-3, 5-6, 10-12, 20-25}"},
+		// touching spans merge
+		{interval.New(4, 4), "{1-6, 10-12, 20-25}"},
+		{interval.New(13, 19), "{1-6, 10-25}"},
+		{interval.New(30, 31), "{1-6, 10-25, 30-31}"},
+		{interval.New(-5, 40), "{-5-40}"},
+	}
+	for _, tc := range cases {
+		s.Insert(tc.iv)
+		if got := s.String(); got != tc.want {
+			t.Errorf("after inserting %v got: %v want: %v", tc.iv, got, tc.want)
+		}
+	}
+}
+
+func TestSubtract(t *testing.T) {
+	s := interval.NewSet(interval.New(0, 20))
+	s.Subtract(interval.New(5, 7))
+	s.Subtract(interval.New(18, 30))
+	s.Subtract(interval.New(-3, 0))
+	if got := s.String(); got != "{1-4, 8-17}" {
+		t.Errorf("wrong spans, got: %v", got)
+	}
+	if s.Len() != 14 {
+		t.Errorf("wrong length, got: %v", s.Len())
+	}
+	if !s.Has(8) || s.Has(5) || s.Has(0) || s.Has(18) {
+		t.Errorf("Has is wrong for %v", s)
+	}
+}
+
+func TestComplementAndGaps(t *testing.T) {
+	s := interval.NewSet(interval.New(-2, 3), interval.New(6, 10), interval.New(15, 30))
+	if got := s.Complement(interval.New(0, 20)).String(); got != "{4-5, 11-14}" {
+		t.Errorf("wrong complement, got: %v", got)
+	}
+	if got := fmt.Sprint(s.Gaps()); got != "[4-5 11-14]" {
+		t.Errorf("wrong gaps, got: %v", got)
+	}
+
+	o := interval.NewSet(interval.New(4, 5))
+	s.Merge(o)
+	if got := s.String(); got != "{-2-10, 15-30}" {
+		t.Errorf("wrong merge, got: %v", got)
+	}
+}
+
+func TestUnsigned(t *testing.T) {
+	s := interval.NewSet(interval.New[uint](0, 3), interval.New[uint](5, 9))
+	s.Subtract(interval.New[uint](0, 0))
+	if got := s.String(); got != "{1-3, 5-9}" || s.Len() != 8 {
+		t.Errorf("wrong spans, got: %v len %v", got, s.Len())
+	}
+}
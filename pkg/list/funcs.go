@@ -0,0 +1,162 @@
+package list
+
+import (
+	"container/heap"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/mathaid"
+)
+
+func Filter[T any](in []T, f func(T) bool) []T {
+	out := make([]T, 0)
+	for _, v := range in {
+		if f(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func Reduce[T any, A any](in []T, init A, f func(A, T) A) A {
+	acc := init
+	for _, v := range in {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+func Sum[T mathaid.Number](in []T) T {
+	return Reduce(in, 0, func(a T, v T) T { return a + v })
+}
+
+// Chunk splits in into consecutive groups of n, the last group may be shorter.
+func Chunk[T any](in []T, n int) [][]T {
+	if n <= 0 {
+		panic("chunk size must be positive")
+	}
+	out := make([][]T, 0, (len(in)+n-1)/n)
+	for i := 0; i < len(in); i += n {
+		end := mathaid.Min(i+n, len(in))
+		out = append(out, in[i:end])
+	}
+	return out
+}
+
+// Windows returns every run of n consecutive elements. The windows share
+// storage with in.
+func Windows[T any](in []T, n int) [][]T {
+	if n <= 0 {
+		panic("window size must be positive")
+	}
+	if len(in) < n {
+		return [][]T{}
+	}
+	out := make([][]T, 0, len(in)-n+1)
+	for i := 0; i+n <= len(in); i++ {
+		out = append(out, in[i:i+n])
+	}
+	return out
+}
+
+// SplitOn splits in at every element matching pred, the separators are dropped.
+func SplitOn[T any](in []T, pred func(T) bool) [][]T {
+	out := make([][]T, 0)
+	start := 0
+	for i, v := range in {
+		if pred(v) {
+			out = append(out, in[start:i])
+			start = i + 1
+		}
+	}
+	return append(out, in[start:])
+}
+
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip pairs up elements of a and b, stopping at the end of the shorter one.
+func Zip[A any, B any](a []A, b []B) []Pair[A, B] {
+	out := make([]Pair[A, B], mathaid.Min(len(a), len(b)))
+	for i := range out {
+		out[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return out
+}
+
+type Indexed[T any] struct {
+	Index int
+	Value T
+}
+
+func Enumerate[T any](in []T) []Indexed[T] {
+	out := make([]Indexed[T], len(in))
+	for i, v := range in {
+		out[i] = Indexed[T]{Index: i, Value: v}
+	}
+	return out
+}
+
+// TopK returns the k largest elements according to less, largest first.
+func TopK[T any](in []T, k int, less func(a, b T) bool) []T {
+	t := NewTop(k, less)
+	for _, v := range in {
+		t.Push(v)
+	}
+	return t.Values()
+}
+
+// Top keeps the k largest values pushed so far, according to less, without
+// holding on to the rest.
+type Top[T any] struct {
+	k int
+	h *minHeap[T]
+}
+
+// NewTop returns an empty Top that keeps k values.
+func NewTop[T any](k int, less func(a, b T) bool) *Top[T] {
+	return &Top[T]{k: k, h: &minHeap[T]{less: less}}
+}
+
+// Push offers v, it's kept if it's among the k largest so far.
+func (t *Top[T]) Push(v T) {
+	if t.k <= 0 {
+		return
+	}
+	if t.h.Len() < t.k {
+		heap.Push(t.h, v)
+	} else if t.h.less(t.h.data[0], v) {
+		t.h.data[0] = v
+		heap.Fix(t.h, 0)
+	}
+}
+
+// Len is how many values are kept, at most k.
+func (t *Top[T]) Len() int {
+	return t.h.Len()
+}
+
+// Values returns the kept values, largest first.
+func (t *Top[T]) Values() []T {
+	c := &minHeap[T]{data: append([]T(nil), t.h.data...), less: t.h.less}
+	out := make([]T, c.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(c).(T)
+	}
+	return out
+}
+
+type minHeap[T any] struct {
+	data []T
+	less func(a, b T) bool
+}
+
+func (h *minHeap[T]) Len() int           { return len(h.data) }
+func (h *minHeap[T]) Less(i, j int) bool { return h.less(h.data[i], h.data[j]) }
+func (h *minHeap[T]) Swap(i, j int)      { h.data[i], h.data[j] = h.data[j], h.data[i] }
+func (h *minHeap[T]) Push(x any)         { h.data = append(h.data, x.(T)) }
+func (h *minHeap[T]) Pop() any {
+	v := h.data[len(h.data)-1]
+	h.data = h.data[0 : len(h.data)-1]
+	return v
+}
@@ -0,0 +1,94 @@
+package list_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/list"
+)
+
+func TestFilterReduce(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5, 6}
+	even := list.Filter(input, func(i int) bool { return i%2 == 0 })
+	if got := fmt.Sprint(even); got != "[2 4 6]" {
+		t.Errorf("filter wrong, got: %v", got)
+	}
+	if got := list.Sum(input); got != 21 {
+		t.Errorf("sum want: 21 got: %v", got)
+	}
+	s := list.Reduce(input, "", func(a string, i int) string { return fmt.Sprintf("%s%d", a, i) })
+	if s != "123456" {
+		t.Errorf("reduce want: 123456 got: %v", s)
+	}
+}
+
+func TestChunkWindows(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5}
+	cases := []struct {
+		name string
+		got  [][]int
+		want string
+	}{
+		{"chunk 2", list.Chunk(input, 2), "[[1 2] [3 4] [5]]"},
+		{"chunk 5", list.Chunk(input, 5), "[[1 2 3 4 5]]"},
+		{"windows 3", list.Windows(input, 3), "[[1 2 3] [2 3 4] [3 4 5]]"},
+		{"windows 6", list.Windows(input, 6), "[]"},
+	}
+	for _, tc := range cases {
+		if got := fmt.Sprint(tc.got); got != tc.want {
+			t.Errorf("%v want: %v got: %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestSplitOn(t *testing.T) {
+	input := []string{"1", "2", "", "3", "", "", "4"}
+	got := list.SplitOn(input, func(s string) bool { return s == "" })
+	if want := "[[1 2] [3] [] [4]]"; fmt.Sprint(got) != want {
+		t.Errorf("want: %v got: %v", want, got)
+	}
+}
+
+func TestZipEnumerate(t *testing.T) {
+	z := list.Zip([]int{1, 2, 3}, []string{"a", "b"})
+	if got := fmt.Sprintf("%v", z); got != "[{1 a} {2 b}]" {
+		t.Errorf("zip wrong, got: %v", got)
+	}
+	e := list.Enumerate([]string{"x", "y"})
+	if e[1].Index != 1 || e[1].Value != "y" {
+		t.Errorf("enumerate wrong, got: %+v", e)
+	}
+}
+
+func TestTopK(t *testing.T) {
+	input := []int{5, 1, 9, 3, 7, 9, 2}
+	less := func(a, b int) bool { return a < b }
+	if got := fmt.Sprint(list.TopK(input, 3, less)); got != "[9 9 7]" {
+		t.Errorf("top 3 wrong, got: %v", got)
+	}
+	if got := fmt.Sprint(list.TopK(input, 10, less)); got != "[9 9 7 5 3 2 1]" {
+		t.Errorf("top 10 wrong, got: %v", got)
+	}
+	if got := list.TopK(input, 0, less); len(got) != 0 {
+		t.Errorf("top 0 wrong, got: %v", got)
+	}
+}
+
+func TestTop(t *testing.T) {
+	top := list.NewTop(2, func(a, b int) bool { return a < b })
+	for i, v := range []int{4, 8, 1, 6} {
+		top.Push(v)
+		// Values doesn't use up what's kept.
+		if i == 1 {
+			if got := fmt.Sprint(top.Values()); got != "[8 4]" {
+				t.Errorf("after 2 pushes got: %v", got)
+			}
+		}
+	}
+	if got := fmt.Sprint(top.Values()); got != "[8 6]" {
+		t.Errorf("top 2 wrong, got: %v", got)
+	}
+	if top.Len() != 2 {
+		t.Errorf("wrong len, got: %v", top.Len())
+	}
+}
@@ -0,0 +1,194 @@
+package list
+
+import "math/rand"
+
+// Element is a stable handle to a value stored in a Sequence. The handle stays
+// valid while the element is moved around, removed and re-inserted.
+type Element[T any] struct {
+	Value T
+
+	left   *Element[T]
+	right  *Element[T]
+	parent *Element[T]
+	prio   uint32
+	size   int
+	seq    *Sequence[T]
+}
+
+// Sequence is an ordered list backed by an implicit treap. Positional inserts,
+// removals and lookups, and finding the position of an element, are all O(log n).
+type Sequence[T any] struct {
+	root *Element[T]
+	rand *rand.Rand
+}
+
+func NewSequence[T any](vals ...T) *Sequence[T] {
+	s := &Sequence[T]{
+		rand: rand.New(rand.NewSource(int64(len(vals)) + 1)),
+	}
+	for _, v := range vals {
+		s.Append(v)
+	}
+	return s
+}
+
+func (s *Sequence[T]) Len() int {
+	return size(s.root)
+}
+
+func (s *Sequence[T]) Append(v T) *Element[T] {
+	return s.InsertAt(s.Len(), v)
+}
+
+// InsertAt puts v at position i, shifting everything at i and after to the right.
+func (s *Sequence[T]) InsertAt(i int, v T) *Element[T] {
+	e := &Element[T]{Value: v}
+	s.InsertElementAt(i, e)
+	return e
+}
+
+// InsertElementAt re-inserts an element that was previously removed.
+func (s *Sequence[T]) InsertElementAt(i int, e *Element[T]) {
+	if e.seq != nil {
+		panic("element is already in a sequence")
+	}
+	if i < 0 || i > s.Len() {
+		panic("sequence index out of range")
+	}
+	e.left, e.right, e.parent = nil, nil, nil
+	e.size = 1
+	e.prio = s.rand.Uint32()
+	e.seq = s
+
+	l, r := split(s.root, i)
+	s.setRoot(merge(merge(l, e), r))
+}
+
+// RemoveAt removes and returns the element at position i.
+func (s *Sequence[T]) RemoveAt(i int) *Element[T] {
+	if i < 0 || i >= s.Len() {
+		panic("sequence index out of range")
+	}
+	l, r := split(s.root, i)
+	e, r := split(r, 1)
+	s.setRoot(merge(l, r))
+	e.parent, e.seq = nil, nil
+	return e
+}
+
+// Remove takes e out of the sequence and returns the position it was at.
+func (s *Sequence[T]) Remove(e *Element[T]) int {
+	i := s.IndexOf(e)
+	if i < 0 {
+		panic("element is not in this sequence")
+	}
+	s.RemoveAt(i)
+	return i
+}
+
+// IndexOf returns the position of e, or -1 if it isn't in the sequence.
+func (s *Sequence[T]) IndexOf(e *Element[T]) int {
+	if e.seq != s {
+		return -1
+	}
+	idx := size(e.left)
+	for n := e; n.parent != nil; n = n.parent {
+		if n == n.parent.right {
+			idx += size(n.parent.left) + 1
+		}
+	}
+	return idx
+}
+
+func (s *Sequence[T]) At(i int) *Element[T] {
+	if i < 0 || i >= s.Len() {
+		panic("sequence index out of range")
+	}
+	n := s.root
+	for {
+		ls := size(n.left)
+		if i < ls {
+			n = n.left
+		} else if i == ls {
+			return n
+		} else {
+			i -= ls + 1
+			n = n.right
+		}
+	}
+}
+
+// Values returns the contents in order.
+func (s *Sequence[T]) Values() []T {
+	out := make([]T, 0, s.Len())
+	var walk func(n *Element[T])
+	walk = func(n *Element[T]) {
+		if n == nil {
+			return
+		}
+		walk(n.left)
+		out = append(out, n.Value)
+		walk(n.right)
+	}
+	walk(s.root)
+	return out
+}
+
+func (s *Sequence[T]) setRoot(n *Element[T]) {
+	s.root = n
+	if n != nil {
+		n.parent = nil
+	}
+}
+
+func size[T any](n *Element[T]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func update[T any](n *Element[T]) {
+	n.size = 1 + size(n.left) + size(n.right)
+	if n.left != nil {
+		n.left.parent = n
+	}
+	if n.right != nil {
+		n.right.parent = n
+	}
+}
+
+// split returns a tree with the first k elements of n, and a tree with the rest.
+func split[T any](n *Element[T], k int) (*Element[T], *Element[T]) {
+	if n == nil {
+		return nil, nil
+	}
+	if size(n.left) >= k {
+		l, r := split(n.left, k)
+		n.left = r
+		update(n)
+		return l, n
+	}
+	l, r := split(n.right, k-size(n.left)-1)
+	n.right = l
+	update(n)
+	return n, r
+}
+
+// merge joins two trees, all elements of a come before those of b.
+func merge[T any](a, b *Element[T]) *Element[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.prio > b.prio {
+		a.right = merge(a.right, b)
+		update(a)
+		return a
+	}
+	b.left = merge(a, b.left)
+	update(b)
+	return b
+}
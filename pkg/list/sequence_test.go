@@ -0,0 +1,66 @@
+package list_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/list"
+)
+
+func TestSequence(t *testing.T) {
+	s := list.NewSequence(1, 2, 3)
+	four := s.InsertAt(0, 4)
+	s.InsertAt(2, 5)
+	if got := fmt.Sprint(s.Values()); got != "[4 1 5 2 3]" {
+		t.Errorf("insert wrong, got: %v", got)
+	}
+	if i := s.IndexOf(four); i != 0 {
+		t.Errorf("index of 4 want: 0 got: %v", i)
+	}
+
+	// move the handle to the end, it should keep its identity.
+	s.Remove(four)
+	if s.IndexOf(four) != -1 {
+		t.Errorf("removed element should not be found")
+	}
+	s.InsertElementAt(s.Len(), four)
+	if got := fmt.Sprint(s.Values()); got != "[1 5 2 3 4]" {
+		t.Errorf("move wrong, got: %v", got)
+	}
+	if i := s.IndexOf(four); i != 4 || s.At(4) != four {
+		t.Errorf("index of moved element want: 4 got: %v", i)
+	}
+	if e := s.RemoveAt(1); e.Value != 5 || s.Len() != 4 {
+		t.Errorf("remove at 1 wrong, got: %v len: %v", e.Value, s.Len())
+	}
+}
+
+// Compare against the slice based list with random moves.
+func TestSequenceRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	s := list.NewSequence[int]()
+	l := list.New[int](0)
+	handles := make([]*list.Element[int], 0)
+	for i := 0; i < 200; i++ {
+		handles = append(handles, s.Append(i))
+		l = append(l, i)
+	}
+	for i := 0; i < 2000; i++ {
+		h := handles[r.Intn(len(handles))]
+		from := s.IndexOf(h)
+		if want := l.Find(h.Value); from != want {
+			t.Fatalf("index of %v want: %v got: %v", h.Value, want, from)
+		}
+		s.Remove(h)
+		var v int
+		v, l = l.RemoveAt(from)
+
+		to := r.Intn(s.Len() + 1)
+		s.InsertElementAt(to, h)
+		l = list.List[int](l).Add(to, v)
+	}
+	if fmt.Sprint(s.Values()) != fmt.Sprint([]int(l)) {
+		t.Errorf("sequence and list diverged")
+	}
+}
@@ -0,0 +1,226 @@
+package mathaid
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrOverflow    = errors.New("integer overflow")
+	ErrNoInverse   = errors.New("no modular inverse")
+	ErrNoSolution  = errors.New("no solution")
+	ErrBadModulus  = errors.New("modulus must be positive")
+	ErrLenMismatch = errors.New("residues and moduli must be the same length")
+)
+
+// Abs is |a|. Like -a, it overflows for the most negative value of a signed
+// integer type and returns it unchanged, so check first if that can happen.
+func Abs[T Signed | Float](a T) T {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+func Sign[T Signed | Float](a T) T {
+	if a < 0 {
+		return -1
+	}
+	if a > 0 {
+		return 1
+	}
+	return 0
+}
+
+func Sum[T Number](in []T) T {
+	var s T
+	for _, v := range in {
+		s += v
+	}
+	return s
+}
+
+func Product[T Number](in []T) T {
+	p := T(1)
+	for _, v := range in {
+		p *= v
+	}
+	return p
+}
+
+// GCD is always non-negative, GCD(0, 0) is 0. The one exception is when the
+// answer is the most negative value of T, which overflows and stays negative.
+func GCD[T Integer](a, b T) T {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// LCM of all values, any zero value makes the result zero.
+func LCM[T Integer](vals ...T) (T, error) {
+	if len(vals) == 0 {
+		return 0, nil
+	}
+	l, err := absInt(vals[0])
+	if err != nil {
+		return 0, fmt.Errorf("lcm of %v: %w", vals, err)
+	}
+	for _, v := range vals[1:] {
+		v, err := absInt(v)
+		if err != nil {
+			return 0, fmt.Errorf("lcm of %v: %w", vals, err)
+		}
+		if l == 0 || v == 0 {
+			return 0, nil
+		}
+		q := l / GCD(l, v)
+		r, err := mulChecked(q, v)
+		if err != nil {
+			return 0, fmt.Errorf("lcm of %v: %w", vals, err)
+		}
+		l = r
+	}
+	return l, nil
+}
+
+// ExtendedGCD returns g, x, y such that a*x + b*y = g = gcd(a, b).
+func ExtendedGCD[T Signed](a, b T) (T, T, T) {
+	oldR, r := a, b
+	oldS, s := T(1), T(0)
+	oldT, t := T(0), T(1)
+	for r != 0 {
+		q := oldR / r
+		oldR, r = r, oldR-q*r
+		oldS, s = s, oldS-q*s
+		oldT, t = t, oldT-q*t
+	}
+	if oldR < 0 {
+		return -oldR, -oldS, -oldT
+	}
+	return oldR, oldS, oldT
+}
+
+// ModInverse returns x in [0, m) such that a*x = 1 (mod m).
+func ModInverse[T Signed](a, m T) (T, error) {
+	if m <= 0 {
+		return 0, ErrBadModulus
+	}
+	g, x, _ := ExtendedGCD(Mod(a, m), m)
+	if g != 1 {
+		return 0, fmt.Errorf("%v mod %v: %w", a, m, ErrNoInverse)
+	}
+	return Mod(x, m), nil
+}
+
+// Mod is the euclidean remainder, always in [0, m) for positive m.
+func Mod[T Integer](a, m T) T {
+	r := a % m
+	if r < 0 {
+		r += m
+	}
+	return r
+}
+
+// MulMod computes a*b mod m without overflowing T. It panics if m isn't
+// positive, check with ErrBadModulus first if m comes from input.
+func MulMod[T Integer](a, b, m T) T {
+	if m <= 0 {
+		panic(fmt.Sprintf("mathaid.MulMod: modulus %v: %v", m, ErrBadModulus))
+	}
+	a, b = Mod(a, m), Mod(b, m)
+	var r T
+	for b > 0 {
+		if b&1 == 1 {
+			r = addMod(r, a, m)
+		}
+		a = addMod(a, a, m)
+		b >>= 1
+	}
+	return r
+}
+
+func ModPow[T Integer](base, exp, m T) (T, error) {
+	if m <= 0 {
+		return 0, ErrBadModulus
+	}
+	if exp < 0 {
+		return 0, fmt.Errorf("negative exponent %v", exp)
+	}
+	r := Mod(1, m)
+	base = Mod(base, m)
+	for exp > 0 {
+		if exp&1 == 1 {
+			r = MulMod(r, base, m)
+		}
+		base = MulMod(base, base, m)
+		exp >>= 1
+	}
+	return r, nil
+}
+
+// CRT finds x such that x = residues[i] (mod moduli[i]) for all i.
+// Moduli do not need to be pairwise coprime. Returns x in [0, lcm) and the lcm of the moduli.
+func CRT[T Signed](residues, moduli []T) (T, T, error) {
+	if len(residues) != len(moduli) {
+		return 0, 0, ErrLenMismatch
+	}
+	x, m := T(0), T(1)
+	for i := range moduli {
+		if moduli[i] <= 0 {
+			return 0, 0, fmt.Errorf("modulus %v: %w", moduli[i], ErrBadModulus)
+		}
+		a, n := Mod(residues[i], moduli[i]), moduli[i]
+
+		g, p, _ := ExtendedGCD(m, n)
+		if (a-x)%g != 0 {
+			return 0, 0, fmt.Errorf("x = %v (mod %v) and x = %v (mod %v): %w", x, m, a, n, ErrNoSolution)
+		}
+		l, err := mulChecked(m/g, n)
+		if err != nil {
+			return 0, 0, fmt.Errorf("crt: %w", err)
+		}
+		// x + m * ((a-x)/g * p mod n/g)
+		k := MulMod(Mod((a-x)/g, n/g), Mod(p, n/g), n/g)
+		x = Mod(x+MulMod(m, k, l), l)
+		m = l
+	}
+	return x, m, nil
+}
+
+// absInt is |a|, with ErrOverflow for the most negative value of a signed T
+// which has no positive counterpart.
+func absInt[T Integer](a T) (T, error) {
+	if a >= 0 {
+		return a, nil
+	}
+	if -a < 0 {
+		return 0, ErrOverflow
+	}
+	return -a, nil
+}
+
+func addMod[T Integer](a, b, m T) T {
+	// a and b are both in [0, m)
+	if a >= m-b {
+		return a - (m - b)
+	}
+	return a + b
+}
+
+func mulChecked[T Integer](a, b T) (T, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	// all ones: -1 for signed types, max for unsigned ones
+	var ones T
+	ones--
+	r := a * b
+	if r/b != a || (a == ones && b == r) || (b == ones && a == r) {
+		return 0, fmt.Errorf("%v * %v: %w", a, b, ErrOverflow)
+	}
+	return r, nil
+}
@@ -0,0 +1,93 @@
+package mathaid_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/mathaid"
+)
+
+func TestGCDLCM(t *testing.T) {
+	if got := mathaid.GCD(12, -18); got != 6 {
+		t.Errorf("GCD(12, -18) want: 6 got: %v", got)
+	}
+	got, err := mathaid.LCM[int64](2, 3, 5, 7, 11, 13, 17, 19, 23)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 223092870 {
+		t.Errorf("LCM of primes want: 223092870 got: %v", got)
+	}
+	if got, _ := mathaid.LCM(4, 6); got != 12 {
+		t.Errorf("LCM(4, 6) want: 12 got: %v", got)
+	}
+	if _, err := mathaid.LCM[int64](math.MaxInt64, 2); !errors.Is(err, mathaid.ErrOverflow) {
+		t.Errorf("expected overflow, got: %v", err)
+	}
+	if _, err := mathaid.LCM[uint8](16, 17); !errors.Is(err, mathaid.ErrOverflow) {
+		t.Errorf("expected overflow, got: %v", err)
+	}
+	if _, err := mathaid.LCM[int8](3, math.MinInt8); !errors.Is(err, mathaid.ErrOverflow) {
+		t.Errorf("expected overflow, got: %v", err)
+	}
+}
+
+func TestModular(t *testing.T) {
+	inv, err := mathaid.ModInverse(3, 11)
+	if err != nil || inv != 4 {
+		t.Errorf("ModInverse(3, 11) want: 4 got: %v, %v", inv, err)
+	}
+	if _, err := mathaid.ModInverse(4, 8); !errors.Is(err, mathaid.ErrNoInverse) {
+		t.Errorf("expected no inverse, got: %v", err)
+	}
+	pow, err := mathaid.ModPow[int64](2, 62, math.MaxInt64)
+	if err != nil || pow != 1<<62 {
+		t.Errorf("ModPow(2, 62) want: %v got: %v, %v", int64(1)<<62, pow, err)
+	}
+	if got := mathaid.MulMod[int64](math.MaxInt64-1, math.MaxInt64-1, math.MaxInt64); got != 1 {
+		t.Errorf("MulMod want: 1 got: %v", got)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("MulMod with modulus 0 didn't panic")
+			}
+		}()
+		mathaid.MulMod(3, 4, 0)
+	}()
+}
+
+func TestCRT(t *testing.T) {
+	cases := []struct {
+		residues []int64
+		moduli   []int64
+		x, m     int64
+	}{
+		{[]int64{2, 3, 2}, []int64{3, 5, 7}, 23, 105},
+		{[]int64{1, 3}, []int64{4, 6}, 9, 12},
+		{[]int64{-1}, []int64{5}, 4, 5},
+	}
+	for _, tc := range cases {
+		x, m, err := mathaid.CRT(tc.residues, tc.moduli)
+		if err != nil {
+			t.Errorf("CRT(%v, %v) unexpected error: %v", tc.residues, tc.moduli, err)
+			continue
+		}
+		if x != tc.x || m != tc.m {
+			t.Errorf("CRT(%v, %v) want: %v,%v got: %v,%v", tc.residues, tc.moduli, tc.x, tc.m, x, m)
+		}
+	}
+	if _, _, err := mathaid.CRT([]int64{1, 2}, []int64{4, 6}); !errors.Is(err, mathaid.ErrNoSolution) {
+		t.Errorf("expected no solution, got: %v", err)
+	}
+}
+
+func TestHelpers(t *testing.T) {
+	if mathaid.Abs(-3) != 3 || mathaid.Sign(-3.5) != -1 || mathaid.Sign(0) != 0 {
+		t.Errorf("Abs/Sign wrong")
+	}
+	if mathaid.Sum([]int{1, 2, 3}) != 6 || mathaid.Product([]int64{2, 3, 4}) != 24 {
+		t.Errorf("Sum/Product wrong")
+	}
+}
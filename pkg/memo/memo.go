@@ -0,0 +1,95 @@
+package memo
+
+import "container/list"
+
+// Memo caches the results of a function by its argument. It is not safe for
+// concurrent use.
+type Memo[K comparable, V any] struct {
+	// MaxSize bounds the number of cached results, evicting the least recently
+	// used one when it's full. Zero means unbounded.
+	MaxSize int
+
+	f     func(K) V
+	cache map[K]*list.Element
+	lru   *list.List
+}
+
+type entry[K comparable, V any] struct {
+	key K
+	val V
+}
+
+// New memoizes f. f may be nil if all lookups go through Do.
+func New[K comparable, V any](f func(K) V) *Memo[K, V] {
+	return &Memo[K, V]{
+		f:     f,
+		cache: make(map[K]*list.Element),
+		lru:   list.New(),
+	}
+}
+
+// NewRecursive memoizes a recursive function, f should recurse through self
+// so that the sub-results are cached too.
+func NewRecursive[K comparable, V any](f func(self func(K) V, k K) V) *Memo[K, V] {
+	m := New[K, V](nil)
+	m.f = func(k K) V {
+		return f(m.Get, k)
+	}
+	return m
+}
+
+// Get returns the cached result for k, calling the memoized function on a miss.
+func (m *Memo[K, V]) Get(k K) V {
+	return m.Do(k, func() V { return m.f(k) })
+}
+
+// Do returns the cached result for k, calling compute on a miss.
+func (m *Memo[K, V]) Do(k K, compute func() V) V {
+	if v, ok := m.Lookup(k); ok {
+		return v
+	}
+	v := compute()
+	m.Put(k, v)
+	return v
+}
+
+func (m *Memo[K, V]) Lookup(k K) (V, bool) {
+	if e, ok := m.cache[k]; ok {
+		m.lru.MoveToFront(e)
+		return e.Value.(*entry[K, V]).val, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (m *Memo[K, V]) Put(k K, v V) {
+	if e, ok := m.cache[k]; ok {
+		e.Value.(*entry[K, V]).val = v
+		m.lru.MoveToFront(e)
+		return
+	}
+	m.cache[k] = m.lru.PushFront(&entry[K, V]{key: k, val: v})
+	for m.MaxSize > 0 && m.lru.Len() > m.MaxSize {
+		oldest := m.lru.Back()
+		m.lru.Remove(oldest)
+		delete(m.cache, oldest.Value.(*entry[K, V]).key)
+	}
+}
+
+// Invalidate forgets the result for k, the next Get recomputes it.
+func (m *Memo[K, V]) Invalidate(k K) {
+	if e, ok := m.cache[k]; ok {
+		m.lru.Remove(e)
+		delete(m.cache, k)
+	}
+}
+
+// Reset forgets everything.
+func (m *Memo[K, V]) Reset() {
+	m.cache = make(map[K]*list.Element)
+	m.lru.Init()
+}
+
+func (m *Memo[K, V]) Len() int {
+	return len(m.cache)
+}
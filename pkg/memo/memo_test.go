@@ -0,0 +1,61 @@
+package memo_test
+
+import (
+	"testing"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/memo"
+)
+
+func TestRecursive(t *testing.T) {
+	calls := 0
+	fib := memo.NewRecursive(func(self func(int) int64, n int) int64 {
+		calls++
+		if n < 2 {
+			return int64(n)
+		}
+		return self(n-1) + self(n-2)
+	})
+	if got := fib.Get(90); got != 2880067194370816120 {
+		t.Errorf("fib(90) wrong, got: %v", got)
+	}
+	if calls != 91 {
+		t.Errorf("each value should be computed once, got %v calls", calls)
+	}
+
+	fib.Invalidate(90)
+	fib.Get(90)
+	if calls != 92 {
+		t.Errorf("only the invalidated value should be recomputed, got %v calls", calls)
+	}
+}
+
+func TestMaxSize(t *testing.T) {
+	calls := 0
+	sq := memo.New(func(n int) int {
+		calls++
+		return n * n
+	})
+	sq.MaxSize = 2
+
+	sq.Get(1)
+	sq.Get(2)
+	sq.Get(1) // 2 is now the least recently used
+	sq.Get(3)
+	if sq.Len() != 2 {
+		t.Errorf("len want: 2 got: %v", sq.Len())
+	}
+	if _, ok := sq.Lookup(2); ok {
+		t.Errorf("2 should have been evicted")
+	}
+	if v, ok := sq.Lookup(1); !ok || v != 1 {
+		t.Errorf("1 should still be cached")
+	}
+	if calls != 3 {
+		t.Errorf("calls want: 3 got: %v", calls)
+	}
+
+	sq.Reset()
+	if v := sq.Do(5, func() int { return -1 }); v != -1 || sq.Len() != 1 {
+		t.Errorf("Do should use the compute func on a miss")
+	}
+}
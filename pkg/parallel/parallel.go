@@ -0,0 +1,100 @@
+package parallel
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Workers is a sensible default for CPU bound work.
+func Workers() int {
+	return runtime.NumCPU()
+}
+
+// Map calls f for every input using at most workers goroutines. Results are
+// returned in input order. The first error cancels the context passed to the
+// remaining calls, and is returned once all running calls have finished.
+func Map[T any, R any](ctx context.Context, workers int, inputs []T, f func(context.Context, T) (R, error)) ([]R, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]R, len(inputs))
+	var once sync.Once
+	var firstErr error
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(inputs); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				r, err := f(ctx, inputs[i])
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				results[i] = r
+			}
+		}()
+	}
+
+feed:
+	for i := range inputs {
+		select {
+		case work <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	if firstErr != nil {
+		return results, firstErr
+	}
+	return results, ctx.Err()
+}
+
+// ForEach is Map for functions without a result.
+func ForEach[T any](ctx context.Context, workers int, inputs []T, f func(context.Context, T) error) error {
+	_, err := Map(ctx, workers, inputs, func(ctx context.Context, in T) (struct{}, error) {
+		return struct{}{}, f(ctx, in)
+	})
+	return err
+}
+
+// First calls f for inputs in parallel until one of them reports found, then
+// cancels the rest. When several inputs could match, which one wins is not
+// defined.
+func First[T any, R any](ctx context.Context, workers int, inputs []T, f func(context.Context, T) (R, bool, error)) (R, bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var result R
+	found := false
+	err := ForEach(ctx, workers, inputs, func(ctx context.Context, in T) error {
+		r, ok, err := f(ctx, in)
+		if err != nil || !ok {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !found {
+			result, found = r, true
+			cancel()
+		}
+		return nil
+	})
+	if found {
+		return result, true, nil
+	}
+	return result, false, err
+}
@@ -0,0 +1,81 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/parallel"
+)
+
+func TestMap(t *testing.T) {
+	inputs := []int{5, 1, 4, 2, 3}
+	var running, maxRunning int32
+	got, err := parallel.Map(context.Background(), 2, inputs, func(ctx context.Context, i int) (int, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(i) * time.Millisecond)
+		return i * 10, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != "[50 10 40 20 30]" {
+		t.Errorf("results out of order, got: %v", got)
+	}
+	if maxRunning > 2 {
+		t.Errorf("more than 2 workers ran at once: %v", maxRunning)
+	}
+}
+
+func TestMapError(t *testing.T) {
+	boom := errors.New("boom")
+	var calls int32
+	inputs := make([]int, 100)
+	_, err := parallel.Map(context.Background(), 1, inputs, func(ctx context.Context, i int) (int, error) {
+		if atomic.AddInt32(&calls, 1) == 3 {
+			return 0, boom
+		}
+		return i, ctx.Err()
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("want: %v got: %v", boom, err)
+	}
+	if calls > 4 {
+		t.Errorf("work should stop after the error, got %v calls", calls)
+	}
+}
+
+func TestFirst(t *testing.T) {
+	inputs := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	got, ok, err := parallel.First(context.Background(), 3, inputs, func(ctx context.Context, i int) (string, bool, error) {
+		if i == 6 {
+			return "six", true, nil
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Duration(i) * time.Millisecond):
+		}
+		return "", false, nil
+	})
+	if err != nil || !ok || got != "six" {
+		t.Errorf("want: six got: %v %v %v", got, ok, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, ok, err := parallel.First(ctx, 2, inputs, func(ctx context.Context, i int) (int, bool, error) {
+		return i, false, nil
+	}); ok || !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled context should be reported, got: %v %v", ok, err)
+	}
+}
@@ -0,0 +1,100 @@
+package straid
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Block is a paragraph of input, a run of non-blank lines.
+type Block struct {
+	Lines []string
+	// Start is the line number of Lines[0].
+	Start int
+
+	name string
+}
+
+func (b *Block) Len() int {
+	return len(b.Lines)
+}
+
+// Line returns the input line number of Lines[i].
+func (b *Block) Line(i int) int {
+	return b.Start + i
+}
+
+// Wrap annotates err with the position of Lines[i], as name:line:col.
+func (b *Block) Wrap(i int, err error) error {
+	return wrapAt(b.name, b.Line(i), err)
+}
+
+func (b *Block) Errorf(i int, format string, args ...any) error {
+	return b.Wrap(i, fmt.Errorf(format, args...))
+}
+
+// Expect returns an error unless the block has exactly n lines.
+func (b *Block) Expect(n int) error {
+	if len(b.Lines) != n {
+		return fmt.Errorf("%s:%d: expected a block of %d lines, found %d", b.name, b.Start, n, len(b.Lines))
+	}
+	return nil
+}
+
+// Scan matches Lines[i], ignoring indentation, against pattern. See Scan.
+func (b *Block) Scan(i int, pattern string, args ...any) error {
+	if i >= len(b.Lines) {
+		return fmt.Errorf("%s:%d: block is missing line %d, expected %q", b.name, b.Line(i), i+1, pattern)
+	}
+	line := strings.TrimLeft(b.Lines[i], " \t")
+	indent := len(b.Lines[i]) - len(line)
+	if err := Scan(pattern, strings.TrimRight(line, " \t"), args...); err != nil {
+		return b.Wrap(i, Offset(err, indent))
+	}
+	return nil
+}
+
+// BlockReader splits input into blank line separated blocks.
+type BlockReader struct {
+	lines *LineReader
+	block *Block
+	name  string
+}
+
+func NewBlockReader(name string, r io.Reader) *BlockReader {
+	return &BlockReader{
+		lines: NewLineReader(name, r),
+		name:  name,
+	}
+}
+
+// Scan reads the next block, any number of blank lines separate blocks.
+func (r *BlockReader) Scan() bool {
+	r.block = nil
+	for r.lines.Scan() {
+		text := r.lines.Text()
+		if strings.TrimSpace(text) == "" {
+			if r.block != nil {
+				return true
+			}
+			continue
+		}
+		if r.block == nil {
+			r.block = &Block{
+				Lines: make([]string, 0),
+				Start: r.lines.Line(),
+				name:  r.name,
+			}
+		}
+		r.block.Lines = append(r.block.Lines, text)
+	}
+	return r.block != nil
+}
+
+func (r *BlockReader) Block() *Block {
+	return r.block
+}
+
+func (r *BlockReader) Err() error {
+	return r.lines.Err()
+}
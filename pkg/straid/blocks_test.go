@@ -0,0 +1,37 @@
+package straid_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
+)
+
+func TestBlockReader(t *testing.T) {
+	input := "\nMonkey 0:\n  Test: divisible by 23\n\n\nMonkey 1:\n  Test: divisible by x\n"
+	r := straid.NewBlockReader("day11 input", strings.NewReader(input))
+
+	starts := make([]int, 0)
+	errs := make([]string, 0)
+	for r.Scan() {
+		b := r.Block()
+		starts = append(starts, b.Start)
+		if err := b.Expect(2); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		var n int
+		if err := b.Scan(1, "Test: divisible by {}", &n); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if err := b.Scan(2, "If true: throw to monkey {}", &n); err == nil {
+			t.Errorf("expected error for missing line")
+		}
+	}
+	if len(starts) != 2 || starts[0] != 2 || starts[1] != 6 {
+		t.Errorf("block starts wrong, got: %v", starts)
+	}
+	want := `day11 input:7:22: "x": not an integer: invalid syntax`
+	if len(errs) != 1 || errs[0] != want {
+		t.Errorf("want: %v got: %v", want, errs)
+	}
+}
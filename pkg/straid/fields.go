@@ -1,19 +1,99 @@
 package straid
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 )
 
+// ParseError describes text that couldn't be parsed. Col is the 1-based column
+// of Text within the string that was being parsed.
+type ParseError struct {
+	Text string
+	Col  int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%q: %v", e.Text, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Offset shifts the column of a ParseError by n, for when the parsed text was
+// a substring starting n bytes into the line. Other errors are returned as is.
+func Offset(err error, n int) error {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		pe.Col += n
+	}
+	return err
+}
+
 func Field(s string, sep string, pos int) string {
 	parts := strings.Split(s, sep)
 	return parts[pos]
 }
 
-func AsInt(s string) int64 {
+// FieldE is Field, but returns an error if there is no field at pos.
+func FieldE(s string, sep string, pos int) (string, error) {
+	parts := strings.Split(s, sep)
+	if pos < 0 || pos >= len(parts) {
+		return "", &ParseError{
+			Text: s,
+			Col:  1,
+			Err:  fmt.Errorf("no field %d separated by %q, only %d fields", pos, sep, len(parts)),
+		}
+	}
+	return parts[pos], nil
+}
+
+// FieldInt parses field pos as an int, errors report the field's column.
+func FieldInt(s string, sep string, pos int) (int64, error) {
+	f, err := FieldE(s, sep, pos)
+	if err != nil {
+		return 0, err
+	}
+	col := 0
+	for _, p := range strings.Split(s, sep)[0:pos] {
+		col += len(p) + len(sep)
+	}
+	i, err := ParseInt(f)
+	if err != nil {
+		return 0, Offset(err, col)
+	}
+	return i, nil
+}
+
+func ParseInt(s string) (int64, error) {
 	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		var ne *strconv.NumError
+		if errors.As(err, &ne) {
+			err = ne.Err
+		}
+		return 0, &ParseError{
+			Text: s,
+			Col:  1,
+			Err:  fmt.Errorf("not an integer: %w", err),
+		}
+	}
+	return i, nil
+}
+
+// MustInt panics if s isn't an integer, handy for quick scripts.
+func MustInt(s string) int64 {
+	i, err := ParseInt(s)
 	if err != nil {
 		panic(err)
 	}
 	return i
 }
+
+// AsInt is an alias for MustInt.
+func AsInt(s string) int64 {
+	return MustInt(s)
+}
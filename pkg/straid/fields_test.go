@@ -0,0 +1,55 @@
+package straid_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
+)
+
+func TestFieldInt(t *testing.T) {
+	if v, err := straid.FieldInt("1,-22,3", ",", 1); err != nil || v != -22 {
+		t.Errorf("want: -22 got: %v, %v", v, err)
+	}
+
+	cases := []struct {
+		in   string
+		pos  int
+		want string
+		col  int
+	}{
+		{"1,2x,3", 1, `"2x": not an integer: invalid syntax`, 3},
+		{"1,2", 2, `"1,2": no field 2 separated by ",", only 2 fields`, 1},
+	}
+	for _, tc := range cases {
+		_, err := straid.FieldInt(tc.in, ",", tc.pos)
+		var pe *straid.ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("%q: expected ParseError, got: %v", tc.in, err)
+			continue
+		}
+		if err.Error() != tc.want || pe.Col != tc.col {
+			t.Errorf("%q: want: %v at %v got: %v at %v", tc.in, tc.want, tc.col, err, pe.Col)
+		}
+	}
+}
+
+func TestLineReader(t *testing.T) {
+	r := straid.NewLineReader("day99 input", strings.NewReader("1\n\n2\n3,x\n"))
+	r.SkipBlank = true
+	lines := 0
+	var err error
+	for r.Scan() {
+		lines++
+		if _, perr := straid.FieldInt(r.Text(), ",", 1); perr != nil && lines == 3 {
+			err = r.Wrap(perr)
+		}
+	}
+	if lines != 3 {
+		t.Errorf("blank lines should be skipped, got %v lines", lines)
+	}
+	if want := `day99 input:4:3: "x": not an integer`; err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("want: %v got: %v", want, err)
+	}
+}
@@ -0,0 +1,74 @@
+package straid
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LineReader wraps a bufio.Scanner and keeps track of the line number so
+// errors can point at the bad input, as name:line:col.
+type LineReader struct {
+	// SkipBlank ignores empty or whitespace only lines.
+	SkipBlank bool
+
+	name    string
+	scanner *bufio.Scanner
+	line    int
+	text    string
+}
+
+func NewLineReader(name string, r io.Reader) *LineReader {
+	return &LineReader{
+		name:    name,
+		scanner: bufio.NewScanner(r),
+	}
+}
+
+func (r *LineReader) Scan() bool {
+	for r.scanner.Scan() {
+		r.line++
+		r.text = r.scanner.Text()
+		if r.SkipBlank && strings.TrimSpace(r.text) == "" {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func (r *LineReader) Text() string {
+	return r.text
+}
+
+// Line is the 1-based number of the line returned by the last Scan.
+func (r *LineReader) Line() int {
+	return r.line
+}
+
+func (r *LineReader) Err() error {
+	return r.scanner.Err()
+}
+
+// Wrap annotates err with the current position. The column comes from a
+// ParseError if there is one in the chain.
+func (r *LineReader) Wrap(err error) error {
+	return wrapAt(r.name, r.line, err)
+}
+
+func (r *LineReader) Errorf(format string, args ...any) error {
+	return r.Wrap(fmt.Errorf(format, args...))
+}
+
+func wrapAt(name string, line int, err error) error {
+	if err == nil {
+		return nil
+	}
+	var pe *ParseError
+	if errors.As(err, &pe) && pe.Col > 0 {
+		return fmt.Errorf("%s:%d:%d: %w", name, line, pe.Col, err)
+	}
+	return fmt.Errorf("%s:%d: %w", name, line, err)
+}
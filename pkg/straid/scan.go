@@ -0,0 +1,104 @@
+package straid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scan matches line against pattern, where each {} in the pattern captures
+// text up to the following literal part. Captures are stored in args in order,
+// which must be *string, *int, *int64 or *[]int64 for a comma or space
+// separated list.
+//
+//	Scan("Sensor at x={}, y={}", line, &x, &y)
+func Scan(pattern string, line string, args ...any) error {
+	literals := strings.Split(pattern, "{}")
+	if len(literals)-1 != len(args) {
+		return fmt.Errorf("pattern %q has %d captures, got %d args", pattern, len(literals)-1, len(args))
+	}
+
+	pos := 0
+	for i, lit := range literals {
+		if !strings.HasPrefix(line[pos:], lit) {
+			return &ParseError{Text: line[pos:], Col: pos + 1, Err: fmt.Errorf("expected %q", lit)}
+		}
+		pos += len(lit)
+		if i == len(args) {
+			break
+		}
+
+		// capture up to the next literal, or the end of the line.
+		next := literals[i+1]
+		end := len(line)
+		if next == "" && i+1 < len(args) {
+			return fmt.Errorf("pattern %q has adjacent captures", pattern)
+		}
+		if next != "" {
+			idx := strings.Index(line[pos:], next)
+			if idx < 0 {
+				return &ParseError{Text: line[pos:], Col: pos + 1, Err: fmt.Errorf("expected %q after capture %d", next, i+1)}
+			}
+			end = pos + idx
+		}
+		if err := assign(line[pos:end], args[i]); err != nil {
+			return Offset(err, pos)
+		}
+		pos = end
+	}
+	if pos != len(line) {
+		return &ParseError{Text: line[pos:], Col: pos + 1, Err: fmt.Errorf("unexpected trailing text")}
+	}
+	return nil
+}
+
+func assign(s string, arg any) error {
+	switch v := arg.(type) {
+	case *string:
+		*v = s
+	case *int64:
+		i, err := ParseInt(s)
+		if err != nil {
+			return err
+		}
+		*v = i
+	case *int:
+		i, err := ParseInt(s)
+		if err != nil {
+			return err
+		}
+		*v = int(i)
+	case *[]int64:
+		list, err := intList(s)
+		if err != nil {
+			return err
+		}
+		*v = list
+	default:
+		return fmt.Errorf("can't scan into %T", arg)
+	}
+	return nil
+}
+
+// intList parses a comma or space separated list of integers. Unlike Ints,
+// anything that isn't an integer is an error.
+func intList(s string) ([]int64, error) {
+	sep := func(c byte) bool { return c == ',' || c == ' ' || c == '\t' }
+	out := make([]int64, 0)
+	for i := 0; i < len(s); {
+		if sep(s[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(s) && !sep(s[j]) {
+			j++
+		}
+		v, err := ParseInt(s[i:j])
+		if err != nil {
+			return nil, Offset(err, i)
+		}
+		out = append(out, v)
+		i = j
+	}
+	return out, nil
+}
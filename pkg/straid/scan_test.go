@@ -0,0 +1,53 @@
+package straid_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mikehelmick/AdventOfCode2022/pkg/straid"
+)
+
+func TestScan(t *testing.T) {
+	pattern := "Sensor at x={}, y={}: closest beacon is at x={}, y={}"
+	var sx, sy, bx, by int
+	if err := straid.Scan(pattern, "Sensor at x=2, y=18: closest beacon is at x=-2, y=15", &sx, &sy, &bx, &by); err != nil {
+		t.Fatal(err)
+	}
+	if sx != 2 || sy != 18 || bx != -2 || by != 15 {
+		t.Errorf("wrong values: %v %v %v %v", sx, sy, bx, by)
+	}
+
+	var name string
+	var items []int64
+	if err := straid.Scan("{} items: {}", "Starting items: 79, 98", &name, &items); err != nil {
+		t.Fatal(err)
+	}
+	if name != "Starting" || fmt.Sprint(items) != "[79 98]" {
+		t.Errorf("wrong values: %v %v", name, items)
+	}
+	err := straid.Scan("{} items: {}", "Starting items: 79, x", &name, &items)
+	var pe *straid.ParseError
+	if !errors.As(err, &pe) || pe.Text != "x" || pe.Col != 21 {
+		t.Errorf("want a ParseError for \"x\" at column 21, got: %v", err)
+	}
+
+	cases := []struct {
+		line string
+		want string
+	}{
+		{"Sensor at x=2, y=18; closest", `"18; closest": expected ": closest beacon is at x=" after capture 2`},
+		{"Sensor at x=2, y=1q: closest beacon is at x=-2, y=15", `"1q": not an integer: invalid syntax`},
+		{"Beacon at x=2", `"Beacon at x=2": expected "Sensor at x="`},
+	}
+	for _, tc := range cases {
+		err := straid.Scan(pattern, tc.line, &sx, &sy, &bx, &by)
+		if err == nil || err.Error() != tc.want {
+			t.Errorf("%q want: %v got: %v", tc.line, tc.want, err)
+		}
+	}
+
+	if err := straid.Scan("a={}.", "a=1. extra", &sx); err == nil {
+		t.Errorf("expected trailing text error")
+	}
+}